@@ -0,0 +1,298 @@
+package testkit
+
+import (
+	"fmt"
+	"time"
+)
+
+// ToNextWeekday returns a TimeAdjustment that advances the virtual clock to
+// the start of the next occurrence of the given day of the week, as observed
+// in loc.
+//
+// If the clock is already on that day, it is advanced by a full week.
+func ToNextWeekday(loc *time.Location, d time.Weekday) TimeAdjustment {
+	if loc == nil {
+		panic(fmt.Sprintf("ToNextWeekday(<nil>, %s): location must not be nil", d))
+	}
+
+	if d < time.Sunday || d > time.Saturday {
+		panic(fmt.Sprintf("ToNextWeekday(%s, %d): weekday is invalid", loc, d))
+	}
+
+	return toNextWeekday{loc, d}
+}
+
+// ToStartOfNextMonth returns a TimeAdjustment that advances the virtual clock
+// to the start of the first day of the next month, as observed in loc.
+func ToStartOfNextMonth(loc *time.Location) TimeAdjustment {
+	if loc == nil {
+		panic("ToStartOfNextMonth(<nil>): location must not be nil")
+	}
+
+	return toStartOfNextMonth{loc}
+}
+
+// ToNextTimeOfDay returns a TimeAdjustment that advances the virtual clock to
+// the next occurrence of a specific time of day, as observed in loc.
+//
+// hour and minute are the hour (0-23) and minute (0-59) of the time of day. If
+// the clock is already at exactly that time, it is advanced by a full day.
+func ToNextTimeOfDay(loc *time.Location, hour, minute int) TimeAdjustment {
+	if loc == nil {
+		panic(fmt.Sprintf("ToNextTimeOfDay(<nil>, %d, %d): location must not be nil", hour, minute))
+	}
+
+	if hour < 0 || hour > 23 {
+		panic(fmt.Sprintf("ToNextTimeOfDay(%s, %d, %d): hour must be between 0 and 23", loc, hour, minute))
+	}
+
+	if minute < 0 || minute > 59 {
+		panic(fmt.Sprintf("ToNextTimeOfDay(%s, %d, %d): minute must be between 0 and 59", loc, hour, minute))
+	}
+
+	return toNextTimeOfDay{loc, hour, minute}
+}
+
+// ByCalendarDays returns a TimeAdjustment that advances the virtual clock by a
+// number of calendar days, as observed in loc.
+//
+// The time of day is retained, even if a daylight saving transition occurs
+// between the current and new times. As such, the clock may advance by more
+// or less than n multiples of 24 hours.
+func ByCalendarDays(loc *time.Location, n int) TimeAdjustment {
+	if loc == nil {
+		panic(fmt.Sprintf("ByCalendarDays(<nil>, %d): location must not be nil", n))
+	}
+
+	if n < 0 {
+		panic(fmt.Sprintf("ByCalendarDays(%s, %d): number of days must not be negative", loc, n))
+	}
+
+	return byCalendarDays{loc, n}
+}
+
+// ByBusinessDays returns a TimeAdjustment that advances the virtual clock by a
+// number of business days, as observed in loc.
+//
+// Saturdays, Sundays and the days that are holidays according to cal are not
+// business days. The time of day is retained, in the same manner as
+// ByCalendarDays().
+func ByBusinessDays(loc *time.Location, n int, cal HolidayCalendar) TimeAdjustment {
+	if loc == nil {
+		panic(fmt.Sprintf("ByBusinessDays(<nil>, %d, ...): location must not be nil", n))
+	}
+
+	if n < 0 {
+		panic(fmt.Sprintf("ByBusinessDays(%s, %d, ...): number of days must not be negative", loc, n))
+	}
+
+	if cal == nil {
+		panic(fmt.Sprintf("ByBusinessDays(%s, %d, <nil>): holiday calendar must not be nil", loc, n))
+	}
+
+	return byBusinessDays{loc, n, cal}
+}
+
+// A HolidayCalendar determines which days are holidays for the purposes of
+// ByBusinessDays().
+type HolidayCalendar interface {
+	// IsHoliday returns true if the day that contains t is a holiday.
+	//
+	// t is midnight at the start of the day, in the location used by the
+	// adjustment.
+	IsHoliday(t time.Time) bool
+}
+
+// HolidayCalendarFunc is an adaptor that allows an ordinary function to be
+// used as a HolidayCalendar.
+type HolidayCalendarFunc func(t time.Time) bool
+
+// IsHoliday returns fn(t).
+func (fn HolidayCalendarFunc) IsHoliday(t time.Time) bool {
+	return fn(t)
+}
+
+// HolidayDates returns a HolidayCalendar that treats the days that contain
+// each of the given times as holidays.
+//
+// Each day is determined by the date of the time in its own location.
+func HolidayDates(dates ...time.Time) HolidayCalendar {
+	return HolidayCalendarFunc(func(t time.Time) bool {
+		y, m, d := t.Date()
+
+		for _, h := range dates {
+			hy, hm, hd := h.Date()
+			if y == hy && m == hm && d == hd {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// startOfDay returns midnight at the start of the day that contains t, in
+// t's location, offset by n days.
+func startOfDay(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+n, 0, 0, 0, 0, t.Location())
+}
+
+// countDays returns a description of n days of the given kind, such as
+// "1 calendar day" or "3 business days".
+func countDays(n int, kind string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s day", kind)
+	}
+
+	return fmt.Sprintf("%d %s days", n, kind)
+}
+
+// toNextWeekday is a TimeAdjustment that advances the clock to the start of a
+// specific day of the week.
+type toNextWeekday struct {
+	loc *time.Location
+	day time.Weekday
+}
+
+func (a toNextWeekday) Description() string {
+	return fmt.Sprintf(
+		"to the start of the next %s (%s)",
+		a.day,
+		a.loc,
+	)
+}
+
+func (a toNextWeekday) Step(before time.Time) time.Time {
+	t := before.In(a.loc)
+
+	n := int(a.day-t.Weekday()+7) % 7
+	if n == 0 {
+		n = 7
+	}
+
+	return startOfDay(t, n)
+}
+
+// toStartOfNextMonth is a TimeAdjustment that advances the clock to the start
+// of the next month.
+type toStartOfNextMonth struct {
+	loc *time.Location
+}
+
+func (a toStartOfNextMonth) Description() string {
+	return fmt.Sprintf(
+		"to the start of the next month (%s)",
+		a.loc,
+	)
+}
+
+func (a toStartOfNextMonth) Step(before time.Time) time.Time {
+	t := before.In(a.loc)
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, a.loc)
+}
+
+// toNextTimeOfDay is a TimeAdjustment that advances the clock to the next
+// occurrence of a specific time of day.
+type toNextTimeOfDay struct {
+	loc          *time.Location
+	hour, minute int
+}
+
+func (a toNextTimeOfDay) Description() string {
+	return fmt.Sprintf(
+		"to the next occurrence of %02d:%02d (%s)",
+		a.hour,
+		a.minute,
+		a.loc,
+	)
+}
+
+func (a toNextTimeOfDay) Step(before time.Time) time.Time {
+	t := before.In(a.loc)
+	y, m, d := t.Date()
+
+	next := time.Date(y, m, d, a.hour, a.minute, 0, 0, a.loc)
+	if !next.After(t) {
+		next = time.Date(y, m, d+1, a.hour, a.minute, 0, 0, a.loc)
+	}
+
+	return next
+}
+
+// byCalendarDays is a TimeAdjustment that advances the clock by a number of
+// calendar days.
+type byCalendarDays struct {
+	loc  *time.Location
+	days int
+}
+
+func (a byCalendarDays) Description() string {
+	return fmt.Sprintf(
+		"by %s (%s)",
+		countDays(a.days, "calendar"),
+		a.loc,
+	)
+}
+
+func (a byCalendarDays) Step(before time.Time) time.Time {
+	return before.In(a.loc).AddDate(0, 0, a.days)
+}
+
+// maxNonBusinessDays is the number of consecutive days that may pass without a
+// business day before a byBusinessDays adjustment gives up.
+//
+// It prevents a holiday calendar that treats every weekday as a holiday from
+// causing the test to run forever.
+const maxNonBusinessDays = 3 * 366
+
+// byBusinessDays is a TimeAdjustment that advances the clock by a number of
+// business days.
+type byBusinessDays struct {
+	loc  *time.Location
+	days int
+	cal  HolidayCalendar
+}
+
+func (a byBusinessDays) Description() string {
+	return fmt.Sprintf(
+		"by %s (%s)",
+		countDays(a.days, "business"),
+		a.loc,
+	)
+}
+
+func (a byBusinessDays) Step(before time.Time) time.Time {
+	t := before.In(a.loc)
+
+	for n, i, prev := 0, 1, 0; n < a.days; i++ {
+		if i-prev > maxNonBusinessDays {
+			panic(fmt.Sprintf(
+				"ByBusinessDays(%s, %d, ...): none of the %d days after %s are business days, check the holiday calendar",
+				a.loc,
+				a.days,
+				maxNonBusinessDays,
+				startOfDay(t, prev).Format(time.DateOnly),
+			))
+		}
+
+		day := startOfDay(t, i)
+
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		if a.cal.IsHoliday(day) {
+			continue
+		}
+
+		n++
+		prev = i
+
+		if n == a.days {
+			return t.AddDate(0, 0, i)
+		}
+	}
+
+	return t
+}
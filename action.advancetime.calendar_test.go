@@ -0,0 +1,212 @@
+package testkit_test
+
+import (
+	"time"
+
+	. "github.com/dogmatiq/testkit"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("calendar time adjustments", func() {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		panic(err)
+	}
+
+	// 2024-10-02 is a Wednesday. Daylight saving time begins in Sydney on
+	// 2024-10-06 at 02:00.
+	wednesday := time.Date(2024, 10, 2, 15, 30, 0, 0, sydney)
+
+	g.DescribeTable(
+		"it advances the clock to the expected time",
+		func(
+			adj TimeAdjustment,
+			before time.Time,
+			after time.Time,
+			desc string,
+		) {
+			gm.Expect(adj.Step(before)).To(gm.BeTemporally("==", after))
+			gm.Expect(adj.Step(before).Location()).To(gm.Equal(after.Location()))
+			gm.Expect(adj.Description()).To(gm.Equal(desc))
+		},
+		g.Entry(
+			"ToNextWeekday() with a later day in the same week",
+			ToNextWeekday(sydney, time.Friday),
+			wednesday,
+			time.Date(2024, 10, 4, 0, 0, 0, 0, sydney),
+			"to the start of the next Friday (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToNextWeekday() with an earlier day in the following week",
+			ToNextWeekday(sydney, time.Monday),
+			wednesday,
+			time.Date(2024, 10, 7, 0, 0, 0, 0, sydney),
+			"to the start of the next Monday (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToNextWeekday() with the current day",
+			ToNextWeekday(sydney, time.Wednesday),
+			wednesday,
+			time.Date(2024, 10, 9, 0, 0, 0, 0, sydney),
+			"to the start of the next Wednesday (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToNextWeekday() observes the day in the given location",
+			ToNextWeekday(sydney, time.Thursday),
+			time.Date(2024, 10, 2, 20, 0, 0, 0, time.UTC), // 06:00 Thursday in Sydney
+			time.Date(2024, 10, 10, 0, 0, 0, 0, sydney),
+			"to the start of the next Thursday (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToStartOfNextMonth()",
+			ToStartOfNextMonth(sydney),
+			wednesday,
+			time.Date(2024, 11, 1, 0, 0, 0, 0, sydney),
+			"to the start of the next month (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToStartOfNextMonth() in December",
+			ToStartOfNextMonth(time.UTC),
+			time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC),
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			"to the start of the next month (UTC)",
+		),
+		g.Entry(
+			"ToNextTimeOfDay() later in the same day",
+			ToNextTimeOfDay(sydney, 17, 0),
+			wednesday,
+			time.Date(2024, 10, 2, 17, 0, 0, 0, sydney),
+			"to the next occurrence of 17:00 (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToNextTimeOfDay() on the following day",
+			ToNextTimeOfDay(sydney, 9, 0),
+			wednesday,
+			time.Date(2024, 10, 3, 9, 0, 0, 0, sydney),
+			"to the next occurrence of 09:00 (Australia/Sydney)",
+		),
+		g.Entry(
+			"ToNextTimeOfDay() at exactly the current time",
+			ToNextTimeOfDay(sydney, 15, 30),
+			wednesday,
+			time.Date(2024, 10, 3, 15, 30, 0, 0, sydney),
+			"to the next occurrence of 15:30 (Australia/Sydney)",
+		),
+		g.Entry(
+			"ByCalendarDays()",
+			ByCalendarDays(sydney, 1),
+			wednesday,
+			time.Date(2024, 10, 3, 15, 30, 0, 0, sydney),
+			"by 1 calendar day (Australia/Sydney)",
+		),
+		g.Entry(
+			"ByCalendarDays() across a daylight saving transition",
+			ByCalendarDays(sydney, 7),
+			wednesday,
+			wednesday.Add(7*24*time.Hour-1*time.Hour),
+			"by 7 calendar days (Australia/Sydney)",
+		),
+		g.Entry(
+			"ByBusinessDays() within the same week",
+			ByBusinessDays(sydney, 2, HolidayDates()),
+			wednesday,
+			time.Date(2024, 10, 4, 15, 30, 0, 0, sydney),
+			"by 2 business days (Australia/Sydney)",
+		),
+		g.Entry(
+			"ByBusinessDays() across a weekend",
+			ByBusinessDays(sydney, 3, HolidayDates()),
+			wednesday,
+			time.Date(2024, 10, 7, 15, 30, 0, 0, sydney),
+			"by 3 business days (Australia/Sydney)",
+		),
+		g.Entry(
+			"ByBusinessDays() across a holiday",
+			ByBusinessDays(
+				sydney,
+				3,
+				HolidayDates(
+					time.Date(2024, 10, 7, 0, 0, 0, 0, sydney), // Labour Day
+				),
+			),
+			wednesday,
+			time.Date(2024, 10, 8, 15, 30, 0, 0, sydney),
+			"by 3 business days (Australia/Sydney)",
+		),
+		g.Entry(
+			"ByBusinessDays() with zero days",
+			ByBusinessDays(
+				sydney,
+				0,
+				HolidayCalendarFunc(func(time.Time) bool { return true }),
+			),
+			wednesday,
+			wednesday,
+			"by 0 business days (Australia/Sydney)",
+		),
+	)
+
+	g.It("can be used with AdvanceTime()", func() {
+		act := AdvanceTime(ToNextTimeOfDay(sydney, 9, 0))
+		gm.Expect(act.Caption()).To(gm.Equal(
+			"advancing time to the next occurrence of 09:00 (Australia/Sydney)",
+		))
+	})
+
+	g.It("panics if the holiday calendar does not have any business days", func() {
+		adj := ByBusinessDays(
+			sydney,
+			1,
+			HolidayCalendarFunc(func(time.Time) bool { return true }),
+		)
+
+		gm.Expect(func() {
+			adj.Step(wednesday)
+		}).To(gm.PanicWith(
+			"ByBusinessDays(Australia/Sydney, 1, ...): none of the 1098 days after 2024-10-02 are business days, check the holiday calendar",
+		))
+	})
+
+	g.DescribeTable(
+		"it panics if the arguments are invalid",
+		func(fn func(), msg string) {
+			gm.Expect(fn).To(gm.PanicWith(msg))
+		},
+		g.Entry(
+			"ToNextWeekday() with a nil location",
+			func() { ToNextWeekday(nil, time.Monday) },
+			"ToNextWeekday(<nil>, Monday): location must not be nil",
+		),
+		g.Entry(
+			"ToNextWeekday() with an invalid weekday",
+			func() { ToNextWeekday(time.UTC, 7) },
+			"ToNextWeekday(UTC, 7): weekday is invalid",
+		),
+		g.Entry(
+			"ToStartOfNextMonth() with a nil location",
+			func() { ToStartOfNextMonth(nil) },
+			"ToStartOfNextMonth(<nil>): location must not be nil",
+		),
+		g.Entry(
+			"ToNextTimeOfDay() with an invalid hour",
+			func() { ToNextTimeOfDay(time.UTC, 24, 0) },
+			"ToNextTimeOfDay(UTC, 24, 0): hour must be between 0 and 23",
+		),
+		g.Entry(
+			"ToNextTimeOfDay() with an invalid minute",
+			func() { ToNextTimeOfDay(time.UTC, 9, 60) },
+			"ToNextTimeOfDay(UTC, 9, 60): minute must be between 0 and 59",
+		),
+		g.Entry(
+			"ByCalendarDays() with a negative number of days",
+			func() { ByCalendarDays(time.UTC, -1) },
+			"ByCalendarDays(UTC, -1): number of days must not be negative",
+		),
+		g.Entry(
+			"ByBusinessDays() with a nil calendar",
+			func() { ByBusinessDays(time.UTC, 1, nil) },
+			"ByBusinessDays(UTC, 1, <nil>): holiday calendar must not be nil",
+		),
+	)
+})
@@ -19,18 +19,54 @@ import (
 // It accepts a TimeAdjustment which calculates the amount of time that the
 // clock is advanced.
 //
-// There are two built-in adjustment types; ToTime() and ByDuration(). Users may
-// provide their own TimeAdjustment implementations that model time-related
-// concepts within the application's business domain.
-func AdvanceTime(adj TimeAdjustment) Action {
+// The built-in adjustment types are ToTime() and ByDuration(), along with the
+// calendar-aware ToNextWeekday(), ToStartOfNextMonth(), ToNextTimeOfDay(),
+// ByCalendarDays() and ByBusinessDays(). Users may provide their own
+// TimeAdjustment implementations that model time-related concepts within the
+// application's business domain.
+//
+// By default, any timeouts that become due are handled at the new time. Use
+// the StepThroughTimeouts() option to handle each timeout at its scheduled
+// time instead.
+func AdvanceTime(adj TimeAdjustment, options ...AdvanceTimeOption) Action {
 	if adj == nil {
 		panic("AdvanceTime(<nil>): adjustment must not be nil")
 	}
 
-	return advanceTimeAction{
-		adj,
-		location.OfCall(),
+	act := advanceTimeAction{
+		adj: adj,
+		loc: location.OfCall(),
+	}
+
+	for _, opt := range options {
+		opt.applyAdvanceTimeOption(&act)
 	}
+
+	return act
+}
+
+// AdvanceTimeOption applies optional settings to an AdvanceTime action.
+type AdvanceTimeOption interface {
+	applyAdvanceTimeOption(*advanceTimeAction)
+}
+
+type advanceTimeOptionFunc func(*advanceTimeAction)
+
+func (f advanceTimeOptionFunc) applyAdvanceTimeOption(a *advanceTimeAction) {
+	f(a)
+}
+
+// StepThroughTimeouts returns an AdvanceTimeOption that causes the virtual
+// clock to stop at the scheduled time of each timeout that becomes due as the
+// clock is advanced.
+//
+// Each timeout is handled with the engine time set to its scheduled time, such
+// that chained timeouts, and the messages they produce, observe the time at
+// which they would occur in a real engine.
+func StepThroughTimeouts() AdvanceTimeOption {
+	return advanceTimeOptionFunc(func(a *advanceTimeAction) {
+		a.stepThroughTimeouts = true
+	})
 }
 
 // A TimeAdjustment describes a change to the test's virtual clock.
@@ -68,11 +104,19 @@ func ByDuration(d time.Duration) TimeAdjustment {
 // advanceTimeAction is an implementation of Action that advances the virtual
 // clock.
 type advanceTimeAction struct {
-	adj TimeAdjustment
-	loc location.Location
+	adj                 TimeAdjustment
+	loc                 location.Location
+	stepThroughTimeouts bool
 }
 
 func (a advanceTimeAction) Caption() string {
+	if a.stepThroughTimeouts {
+		return fmt.Sprintf(
+			"advancing time %s, stepping through timeouts",
+			a.adj.Description(),
+		)
+	}
+
 	return fmt.Sprintf(
 		"advancing time %s",
 		a.adj.Description(),
@@ -98,6 +142,10 @@ func (a advanceTimeAction) Do(ctx context.Context, s ActionScope) error {
 
 	*s.VirtualClock = now
 
+	if a.stepThroughTimeouts {
+		return s.Engine.TickUntil(ctx, now, s.OperationOptions...)
+	}
+
 	// There is already an engine.WithCurrentTime() based on the virtual clock
 	// in options slice. Because we have just updated the clock we need to
 	// override it for this one engine tick.
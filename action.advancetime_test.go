@@ -1,6 +1,7 @@
 package testkit_test
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -172,4 +173,207 @@ var _ = g.Describe("func AdvanceTime()", func() {
 			}).To(gm.PanicWith("ByDuration(-1s): duration must not be negative"))
 		})
 	})
+
+	g.When("passed the StepThroughTimeouts() option", func() {
+		g.BeforeEach(func() {
+			app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "140ca29b-7a05-4f26-968b-6285255e6d8a")
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "3c0e6a2f-8b1d-4e5a-9f7c-2d4b6e8a0c1f")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeA]](),
+							dogma.SchedulesTimeout[TimeoutStub[TypeA]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ScheduleTimeout(TimeoutA1, startTime.Add(1*time.Hour))
+						s.ScheduleTimeout(TimeoutA2, startTime.Add(2*time.Hour))
+						return nil
+					},
+					HandleTimeoutFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessTimeoutScope,
+						m dogma.Timeout,
+					) error {
+						if m == TimeoutA1 {
+							s.ScheduleTimeout(TimeoutA3, s.ScheduledFor().Add(30*time.Minute))
+						}
+						return nil
+					},
+				})
+			}
+
+			test = Begin(
+				t,
+				app,
+				StartTimeAt(startTime),
+				WithUnsafeOperationOptions(
+					engine.WithObserver(buf),
+				),
+			)
+		})
+
+		g.It("performs a tick at the scheduled time of each timeout", func() {
+			test.Prepare(
+				RecordEvent(EventA1),
+				AdvanceTime(
+					ByDuration(3*time.Hour),
+					StepThroughTimeouts(),
+				),
+			)
+
+			var times []time.Time
+			for _, f := range buf.Facts() {
+				if x, ok := f.(fact.TickCycleBegun); ok {
+					times = append(times, x.EngineTime)
+				}
+			}
+
+			gm.Expect(times).To(gm.Equal(
+				[]time.Time{
+					startTime.Add(1 * time.Hour),
+					startTime.Add(90 * time.Minute),
+					startTime.Add(2 * time.Hour),
+					startTime.Add(3 * time.Hour),
+				},
+			))
+		})
+
+		g.It("does not handle timeouts that are scheduled after the new time", func() {
+			test.Prepare(
+				RecordEvent(EventA1),
+				AdvanceTime(
+					ByDuration(1*time.Hour),
+					StepThroughTimeouts(),
+				),
+			)
+
+			gm.Expect(test.PendingTimeouts("<process>")).To(gm.Equal(
+				[]dogma.Timeout{TimeoutA3, TimeoutA2},
+			))
+		})
+
+		g.It("handles overdue timeouts without reversing time", func() {
+			test.Prepare(
+				RecordEvent(EventA1),
+			)
+
+			// Advance the clock past the scheduled times of the timeouts
+			// without giving the process an opportunity to handle them.
+			test.DisableHandlers("<process>")
+			test.Prepare(
+				AdvanceTime(ByDuration(3 * time.Hour)),
+			)
+			test.EnableHandlers("<process>")
+
+			n := len(buf.Facts())
+
+			test.Prepare(
+				AdvanceTime(
+					ByDuration(1*time.Hour),
+					StepThroughTimeouts(),
+				),
+			)
+
+			var times []time.Time
+			for _, f := range buf.Facts()[n:] {
+				if x, ok := f.(fact.TickCycleBegun); ok {
+					times = append(times, x.EngineTime)
+				}
+			}
+
+			gm.Expect(times).NotTo(gm.BeEmpty())
+			for _, x := range times {
+				gm.Expect(x).NotTo(gm.BeTemporally("<", startTime.Add(3*time.Hour)))
+			}
+			gm.Expect(test.PendingTimeouts("<process>")).To(gm.BeEmpty())
+		})
+
+		g.It("fails the test if the tick limit is exceeded", func() {
+			app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "140ca29b-7a05-4f26-968b-6285255e6d8a")
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "3c0e6a2f-8b1d-4e5a-9f7c-2d4b6e8a0c1f")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeA]](),
+							dogma.SchedulesTimeout[TimeoutStub[TypeA]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ScheduleTimeout(TimeoutA1, startTime.Add(1*time.Nanosecond))
+						return nil
+					},
+					HandleTimeoutFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessTimeoutScope,
+						m dogma.Timeout,
+					) error {
+						// Always reschedule the timeout in the near future.
+						s.ScheduleTimeout(m, s.ScheduledFor().Add(1*time.Nanosecond))
+						return nil
+					},
+				})
+			}
+
+			t.FailSilently = true
+
+			Begin(
+				t,
+				app,
+				StartTimeAt(startTime),
+				WithUnsafeEngineOptions(
+					engine.WithTickLimit(5),
+				),
+			).Prepare(
+				RecordEvent(EventA1),
+				AdvanceTime(
+					ByDuration(1*time.Hour),
+					StepThroughTimeouts(),
+				),
+			)
+
+			gm.Expect(t.Failed()).To(gm.BeTrue())
+			gm.Expect(t.Logs).To(gm.ContainElement(
+				fmt.Sprintf(
+					"advancing time aborted after 5 ticks, the tick limit of 5 was exceeded with a timeout pending at %s",
+					startTime.Add(6*time.Nanosecond).Format(time.RFC3339Nano),
+				),
+			))
+		})
+
+		g.It("produces the expected caption", func() {
+			test.Prepare(
+				AdvanceTime(
+					ByDuration(3*time.Second),
+					StepThroughTimeouts(),
+				),
+			)
+
+			gm.Expect(t.Logs).To(gm.ContainElement(
+				"--- advancing time by 3s, stepping through timeouts ---",
+			))
+		})
+	})
 })
@@ -0,0 +1,165 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/internal/inflect"
+	"github.com/dogmatiq/testkit/location"
+)
+
+// GivenAggregateHistory returns an Action that appends events to the history
+// of an aggregate instance without invoking the aggregate message handler.
+//
+// It places the instance into the state it would be in had it recorded the
+// events, without having to execute the commands that would ordinarily cause
+// them to be recorded. It is typically used with Test.Prepare() to write
+// "given, when, then" style tests.
+//
+// handler is the name of the aggregate message handler, and id is the ID of
+// the instance. Each of the events must be of a type that is recorded by the
+// handler.
+func GivenAggregateHistory(handler, id string, events ...dogma.Event) Action {
+	if handler == "" {
+		panic(fmt.Sprintf("GivenAggregateHistory(%#v, %#v): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("GivenAggregateHistory(%#v, %#v): instance ID must not be empty", handler, id))
+	}
+
+	if len(events) == 0 {
+		panic(fmt.Sprintf("GivenAggregateHistory(%#v, %#v): at least one event must be provided", handler, id))
+	}
+
+	for _, m := range events {
+		if m == nil {
+			panic(fmt.Sprintf("GivenAggregateHistory(%#v, %#v): events must not be nil", handler, id))
+		}
+	}
+
+	return givenAggregateHistoryAction{
+		handler: handler,
+		id:      id,
+		events:  events,
+		loc:     location.OfCall(),
+	}
+}
+
+// GivenProcessRoot returns an Action that sets the root of a process instance
+// without invoking the process message handler.
+//
+// It places the instance into a particular state without having to dispatch
+// the events that would ordinarily cause the process to reach that state. If
+// the instance already exists its root is replaced.
+//
+// handler is the name of the process message handler, and id is the ID of the
+// instance.
+func GivenProcessRoot(handler, id string, r dogma.ProcessRoot) Action {
+	if handler == "" {
+		panic(fmt.Sprintf("GivenProcessRoot(%#v, %#v, ...): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("GivenProcessRoot(%#v, %#v, ...): instance ID must not be empty", handler, id))
+	}
+
+	if r == nil {
+		panic(fmt.Sprintf("GivenProcessRoot(%#v, %#v, <nil>): root must not be nil", handler, id))
+	}
+
+	return givenProcessRootAction{
+		handler: handler,
+		id:      id,
+		root:    r,
+		loc:     location.OfCall(),
+	}
+}
+
+// givenAggregateHistoryAction is an implementation of Action that seeds the
+// history of an aggregate instance.
+type givenAggregateHistoryAction struct {
+	handler string
+	id      string
+	events  []dogma.Event
+	loc     location.Location
+}
+
+func (a givenAggregateHistoryAction) Caption() string {
+	return fmt.Sprintf(
+		"seeding the '%s' instance of the '%s' aggregate with %s",
+		a.id,
+		a.handler,
+		inflect.Sprintf(message.EventKind, "%d <messages>", len(a.events)),
+	)
+}
+
+func (a givenAggregateHistoryAction) Location() location.Location {
+	return a.loc
+}
+
+func (a givenAggregateHistoryAction) ConfigurePredicate(*PredicateOptions) {
+}
+
+func (a givenAggregateHistoryAction) Do(ctx context.Context, s ActionScope) error {
+	// TODO: These checks should result in information being added to the
+	// report, not just returning an error.
+	//
+	// See https://github.com/dogmatiq/testkit/issues/162
+	if _, err := lookupHandlerOfType(s.App, a.handler, configkit.AggregateHandlerType); err != nil {
+		return err
+	}
+
+	return s.Engine.SeedAggregateHistory(
+		ctx,
+		a.handler,
+		a.id,
+		a.events,
+		s.OperationOptions...,
+	)
+}
+
+// givenProcessRootAction is an implementation of Action that seeds the root of
+// a process instance.
+type givenProcessRootAction struct {
+	handler string
+	id      string
+	root    dogma.ProcessRoot
+	loc     location.Location
+}
+
+func (a givenProcessRootAction) Caption() string {
+	return fmt.Sprintf(
+		"seeding the root of the '%s' instance of the '%s' process",
+		a.id,
+		a.handler,
+	)
+}
+
+func (a givenProcessRootAction) Location() location.Location {
+	return a.loc
+}
+
+func (a givenProcessRootAction) ConfigurePredicate(*PredicateOptions) {
+}
+
+func (a givenProcessRootAction) Do(ctx context.Context, s ActionScope) error {
+	// TODO: These checks should result in information being added to the
+	// report, not just returning an error.
+	//
+	// See https://github.com/dogmatiq/testkit/issues/162
+	if _, err := lookupHandlerOfType(s.App, a.handler, configkit.ProcessHandlerType); err != nil {
+		return err
+	}
+
+	return s.Engine.SeedProcessRoot(
+		ctx,
+		a.handler,
+		a.id,
+		a.root,
+		s.OperationOptions...,
+	)
+}
@@ -0,0 +1,372 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func GivenAggregateHistory()", func() {
+	var (
+		app       *ApplicationStub
+		aggregate *AggregateMessageHandlerStub
+		t         *testingmock.T
+		buf       *fact.Buffer
+		test      *Test
+	)
+
+	g.BeforeEach(func() {
+		aggregate = &AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "a4f1c3e2-6b5d-4e8f-9a7c-0b1d2e3f4a5b")
+				c.Routes(
+					dogma.HandlesCommand[CommandStub[TypeA]](),
+					dogma.RecordsEvent[EventStub[TypeA]](),
+				)
+			},
+			RouteCommandToInstanceFunc: func(dogma.Command) string {
+				return "<instance>"
+			},
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "e5d4c3b2-a190-4f8e-8d7c-6b5a49382716")
+				c.RegisterAggregate(aggregate)
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "16273849-5a6b-4c7d-8e9f-a0b1c2d3e4f5")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeA]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "", false, nil
+					},
+				})
+			},
+		}
+
+		t = &testingmock.T{}
+		buf = &fact.Buffer{}
+
+		test = Begin(
+			t,
+			app,
+			WithUnsafeOperationOptions(
+				engine.WithObserver(buf),
+			),
+		)
+	})
+
+	g.It("applies the events to the root when the next command is handled", func() {
+		var applied []dogma.Event
+		aggregate.HandleCommandFunc = func(
+			r dogma.AggregateRoot,
+			_ dogma.AggregateCommandScope,
+			_ dogma.Command,
+		) {
+			applied = r.(*AggregateRootStub).AppliedEvents
+		}
+
+		test.Prepare(
+			GivenAggregateHistory("<aggregate>", "<instance>", EventA1, EventA2),
+			GivenAggregateHistory("<aggregate>", "<instance>", EventA3),
+			ExecuteCommand(CommandA1),
+		)
+
+		gm.Expect(applied).To(gm.Equal(
+			[]dogma.Event{EventA1, EventA2, EventA3},
+		))
+	})
+
+	g.It("does not invoke the handler", func() {
+		aggregate.HandleCommandFunc = func(
+			dogma.AggregateRoot,
+			dogma.AggregateCommandScope,
+			dogma.Command,
+		) {
+			g.Fail("unexpected call")
+		}
+
+		test.Prepare(
+			GivenAggregateHistory("<aggregate>", "<instance>", EventA1),
+		)
+	})
+
+	g.It("causes the instance to exist", func() {
+		aggregate.HandleCommandFunc = func(
+			_ dogma.AggregateRoot,
+			s dogma.AggregateCommandScope,
+			_ dogma.Command,
+		) {
+			s.Destroy()
+		}
+
+		test.
+			Prepare(
+				GivenAggregateHistory("<aggregate>", "<instance>", EventA1),
+			).
+			Expect(
+				ExecuteCommand(CommandA1),
+				ToDestroyAggregateInstance("<aggregate>", "<instance>"),
+			)
+
+		gm.Expect(t.Failed()).To(gm.BeFalse())
+	})
+
+	g.It("records a fact", func() {
+		test.Prepare(
+			GivenAggregateHistory("<aggregate>", "<instance>", EventA1),
+		)
+
+		var seeded []fact.AggregateHistorySeeded
+		for _, f := range buf.Facts() {
+			if x, ok := f.(fact.AggregateHistorySeeded); ok {
+				seeded = append(seeded, x)
+			}
+		}
+
+		gm.Expect(seeded).To(gm.HaveLen(1))
+		gm.Expect(seeded[0].InstanceID).To(gm.Equal("<instance>"))
+		gm.Expect(seeded[0].Root).To(gm.Equal(
+			&AggregateRootStub{
+				AppliedEvents: []dogma.Event{EventA1},
+			},
+		))
+		gm.Expect(seeded[0].EventEnvelopes).To(gm.HaveLen(1))
+		gm.Expect(seeded[0].EventEnvelopes[0].Message).To(gm.Equal(EventA1))
+		gm.Expect(seeded[0].EventEnvelopes[0].Origin.InstanceID).To(gm.Equal("<instance>"))
+	})
+
+	g.It("produces the expected caption", func() {
+		test.Prepare(
+			GivenAggregateHistory("<aggregate>", "<instance>", EventA1, EventA2),
+		)
+
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"--- seeding the '<instance>' instance of the '<aggregate>' aggregate with 2 events ---",
+		))
+	})
+
+	g.It("fails the test if the handler is not an aggregate", func() {
+		t.FailSilently = true
+
+		test.Prepare(
+			GivenAggregateHistory("<process>", "<instance>", EventA1),
+		)
+
+		gm.Expect(t.Failed()).To(gm.BeTrue())
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"'<process>' is a process message handler, not an aggregate message handler",
+		))
+	})
+
+	g.It("fails the test if the event is not recorded by the handler", func() {
+		t.FailSilently = true
+
+		test.Prepare(
+			GivenAggregateHistory("<aggregate>", "<instance>", EventB1),
+		)
+
+		gm.Expect(t.Failed()).To(gm.BeTrue())
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"cannot seed the history of the '<aggregate>' aggregate with a stubs.EventStub[TypeB] event, it is not recorded by that handler",
+		))
+	})
+
+	g.It("fails the test if the event is invalid", func() {
+		t.FailSilently = true
+
+		test.Prepare(
+			GivenAggregateHistory(
+				"<aggregate>",
+				"<instance>",
+				EventStub[TypeA]{ValidationError: "<invalid>"},
+			),
+		)
+
+		gm.Expect(t.Failed()).To(gm.BeTrue())
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"cannot seed the history of the '<aggregate>' aggregate with an invalid stubs.EventStub[TypeA] event: <invalid>",
+		))
+	})
+
+	g.It("panics if the handler name is empty", func() {
+		gm.Expect(func() {
+			GivenAggregateHistory("", "<instance>", EventA1)
+		}).To(gm.PanicWith(`GivenAggregateHistory("", "<instance>"): handler name must not be empty`))
+	})
+
+	g.It("panics if the instance ID is empty", func() {
+		gm.Expect(func() {
+			GivenAggregateHistory("<aggregate>", "", EventA1)
+		}).To(gm.PanicWith(`GivenAggregateHistory("<aggregate>", ""): instance ID must not be empty`))
+	})
+
+	g.It("panics if there are no events", func() {
+		gm.Expect(func() {
+			GivenAggregateHistory("<aggregate>", "<instance>")
+		}).To(gm.PanicWith(`GivenAggregateHistory("<aggregate>", "<instance>"): at least one event must be provided`))
+	})
+})
+
+var _ = g.Describe("func GivenProcessRoot()", func() {
+	var (
+		app     *ApplicationStub
+		process *ProcessMessageHandlerStub
+		t       *testingmock.T
+		buf     *fact.Buffer
+		test    *Test
+	)
+
+	g.BeforeEach(func() {
+		process = &ProcessMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProcessConfigurer) {
+				c.Identity("<process>", "27384950-6a7b-4c8d-9e0f-a1b2c3d4e5f6")
+				c.Routes(
+					dogma.HandlesEvent[EventStub[TypeA]](),
+					dogma.ExecutesCommand[CommandStub[TypeA]](),
+				)
+			},
+			RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+				return "<instance>", true, nil
+			},
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "38495061-7b8c-4d9e-8f0a-b1c2d3e4f5a6")
+				c.RegisterProcess(process)
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "49506172-8c9d-4e0f-9a1b-c2d3e4f5a6b7")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+				})
+			},
+		}
+
+		t = &testingmock.T{}
+		buf = &fact.Buffer{}
+
+		test = Begin(
+			t,
+			app,
+			WithUnsafeOperationOptions(
+				engine.WithObserver(buf),
+			),
+		)
+	})
+
+	g.It("passes the root to the handler when the next event is handled", func() {
+		root := &ProcessRootStub{Value: "<value>"}
+
+		var actual dogma.ProcessRoot
+		process.HandleEventFunc = func(
+			_ context.Context,
+			r dogma.ProcessRoot,
+			_ dogma.ProcessEventScope,
+			_ dogma.Event,
+		) error {
+			actual = r
+			return nil
+		}
+
+		test.Prepare(
+			GivenProcessRoot("<process>", "<instance>", root),
+			RecordEvent(EventA1),
+		)
+
+		gm.Expect(actual).To(gm.Equal(root))
+	})
+
+	g.It("does not retain the root", func() {
+		root := &ProcessRootStub{Value: "<value>"}
+
+		var actual dogma.ProcessRoot
+		process.HandleEventFunc = func(
+			_ context.Context,
+			r dogma.ProcessRoot,
+			_ dogma.ProcessEventScope,
+			_ dogma.Event,
+		) error {
+			actual = r
+			return nil
+		}
+
+		test.Prepare(
+			GivenProcessRoot("<process>", "<instance>", root),
+		)
+
+		root.Value = "<modified>"
+
+		test.Prepare(
+			RecordEvent(EventA1),
+		)
+
+		gm.Expect(actual).To(gm.Equal(
+			&ProcessRootStub{Value: "<value>"},
+		))
+	})
+
+	g.It("records a fact", func() {
+		root := &ProcessRootStub{Value: "<value>"}
+
+		test.Prepare(
+			GivenProcessRoot("<process>", "<instance>", root),
+		)
+
+		var seeded []fact.ProcessRootSeeded
+		for _, f := range buf.Facts() {
+			if x, ok := f.(fact.ProcessRootSeeded); ok {
+				seeded = append(seeded, x)
+			}
+		}
+
+		gm.Expect(seeded).To(gm.HaveLen(1))
+		gm.Expect(seeded[0].Handler.Identity().Name).To(gm.Equal("<process>"))
+		gm.Expect(seeded[0].InstanceID).To(gm.Equal("<instance>"))
+		gm.Expect(seeded[0].Root).To(gm.BeIdenticalTo(root))
+	})
+
+	g.It("produces the expected caption", func() {
+		test.Prepare(
+			GivenProcessRoot("<process>", "<instance>", &ProcessRootStub{}),
+		)
+
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"--- seeding the root of the '<instance>' instance of the '<process>' process ---",
+		))
+	})
+
+	g.It("fails the test if the handler is not a process", func() {
+		t.FailSilently = true
+
+		test.Prepare(
+			GivenProcessRoot("<aggregate>", "<instance>", &ProcessRootStub{}),
+		)
+
+		gm.Expect(t.Failed()).To(gm.BeTrue())
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"'<aggregate>' is an aggregate message handler, not a process message handler",
+		))
+	})
+
+	g.It("panics if the root is nil", func() {
+		gm.Expect(func() {
+			GivenProcessRoot("<process>", "<instance>", nil)
+		}).To(gm.PanicWith(`GivenProcessRoot("<process>", "<instance>", <nil>): root must not be nil`))
+	})
+})
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
+)
+
+// causationChain tracks the causal relationships between the messages that
+// are dispatched within a single dispatch cycle.
+type causationChain struct {
+	envelopes map[string]*envelope.Envelope
+	depths    map[string]int
+}
+
+// newCausationChain returns a new, empty causation chain.
+func newCausationChain() *causationChain {
+	return &causationChain{
+		envelopes: map[string]*envelope.Envelope{},
+		depths:    map[string]int{},
+	}
+}
+
+// Add records env in the chain and returns its causation depth.
+//
+// The depth of a message that was not caused by any message within the chain
+// is 1.
+func (c *causationChain) Add(env *envelope.Envelope) int {
+	depth := 1
+
+	if env.CausationID != env.MessageID {
+		if d, ok := c.depths[env.CausationID]; ok {
+			depth = d + 1
+		}
+	}
+
+	c.envelopes[env.MessageID] = env
+	c.depths[env.MessageID] = depth
+
+	return depth
+}
+
+// Cycle returns the shortest sequence of message types that ends with the type
+// of the message in env and that occurs at least twice in a row at the end of
+// its causation chain, in the order the messages were produced.
+//
+// The first and last elements of the returned slice are the same. It returns
+// nil if there is no such sequence.
+func (c *causationChain) Cycle(env *envelope.Envelope) []message.Type {
+	// types is the causation chain of env, most recent first.
+	var types []message.Type
+
+	for {
+		types = append(types, message.TypeOf(env.Message))
+
+		if env.CausationID == env.MessageID {
+			break
+		}
+
+		cause, ok := c.envelopes[env.CausationID]
+		if !ok {
+			break
+		}
+
+		env = cause
+	}
+
+	for n := 1; n*2 <= len(types); n++ {
+		if slices.Equal(types[:n], types[n:n*2]) {
+			cycle := slices.Clone(types[:n+1])
+			slices.Reverse(cycle)
+			return cycle
+		}
+	}
+
+	return nil
+}
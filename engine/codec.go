@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec marshals values to and from a binary representation.
+//
+// Codecs are used to verify that the values managed by the engine survive
+// being persisted, as they would be by a real engine.
+type Codec interface {
+	// Marshal returns the binary representation of v.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal populates v from its binary representation. v is always a
+	// pointer.
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JSONCodec is a Codec that uses Go's standard JSON encoding.
+	JSONCodec Codec = jsonCodec{}
+
+	// GobCodec is a Codec that uses Go's standard gob encoding.
+	//
+	// Values stored in interface fields must be registered using
+	// [gob.Register].
+	GobCodec Codec = gobCodec{}
+
+	// ProtobufCodec is a Codec that uses the protocol buffers binary
+	// encoding. It can only be used with values that implement
+	// [proto.Message].
+	ProtobufCodec Codec = protobufCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement proto.Message", v)
+	}
+
+	return proto.Marshal(m)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T does not implement proto.Message", v)
+	}
+
+	return proto.Unmarshal(data, m)
+}
@@ -0,0 +1,50 @@
+package engine_test
+
+import (
+	. "github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/internal/fixtures"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ = g.Describe("type Codec", func() {
+	type value struct {
+		Name string
+	}
+
+	g.DescribeTable(
+		"it marshals and unmarshals values",
+		func(c Codec) {
+			data, err := c.Marshal(&value{"<name>"})
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			var v value
+			err = c.Unmarshal(data, &v)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(v).To(gm.Equal(value{"<name>"}))
+		},
+		g.Entry("JSONCodec", JSONCodec),
+		g.Entry("GobCodec", GobCodec),
+	)
+
+	g.Describe("var ProtobufCodec", func() {
+		g.It("marshals and unmarshals protocol buffers messages", func() {
+			data, err := ProtobufCodec.Marshal(&fixtures.ProtoMessage{Value: "<value>"})
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			v := &fixtures.ProtoMessage{}
+			err = ProtobufCodec.Unmarshal(data, v)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(proto.Equal(v, &fixtures.ProtoMessage{Value: "<value>"})).To(gm.BeTrue())
+		})
+
+		g.It("returns an error if the value is not a protocol buffers message", func() {
+			_, err := ProtobufCodec.Marshal(&value{})
+			gm.Expect(err).To(gm.MatchError("*engine_test.value does not implement proto.Message"))
+
+			err = ProtobufCodec.Unmarshal(nil, &value{})
+			gm.Expect(err).To(gm.MatchError("*engine_test.value does not implement proto.Message"))
+		})
+	})
+})
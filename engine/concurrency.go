@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+// handleResult is the result of passing an envelope to a single controller.
+type handleResult struct {
+	Controller controller
+	Envelopes  []*envelope.Envelope
+	Error      error
+}
+
+// handleAll passes env to each of the given controllers.
+//
+// If concurrent handling is enabled the controllers are invoked on separate
+// goroutines, otherwise they are invoked one at a time. In either case the
+// results are returned in the same order as controllers.
+func (e *Engine) handleAll(
+	ctx context.Context,
+	oo *operationOptions,
+	env *envelope.Envelope,
+	controllers []controller,
+) []handleResult {
+	results := make([]handleResult, len(controllers))
+
+	if !oo.concurrentHandling || len(controllers) < 2 {
+		for i, c := range controllers {
+			envs, err := e.handle(ctx, oo, env, c)
+			results[i] = handleResult{c, envs, err}
+		}
+
+		return results
+	}
+
+	// Take a copy of the options so that the observers are notified of facts
+	// from one goroutine at a time.
+	concurrent := *oo
+	concurrent.observers = fact.ObserverGroup{
+		&serializedObserver{
+			next: oo.observers,
+		},
+	}
+
+	var (
+		g      sync.WaitGroup
+		panics = make([]any, len(controllers))
+	)
+
+	for i, c := range controllers {
+		g.Add(1)
+
+		go func() {
+			defer g.Done()
+
+			// Any panic that is not recovered by the controller is captured so
+			// that it can be re-raised on the caller's goroutine.
+			defer func() {
+				panics[i] = recover()
+			}()
+
+			envs, err := e.handle(ctx, &concurrent, env, c)
+			results[i] = handleResult{c, envs, err}
+		}()
+	}
+
+	g.Wait()
+
+	for _, p := range panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+
+	return results
+}
+
+// serializedObserver is a fact.Observer that forwards facts to another
+// observer, notifying it of at most one fact at a time.
+type serializedObserver struct {
+	m    sync.Mutex
+	next fact.Observer
+}
+
+func (o *serializedObserver) Notify(f fact.Fact) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.next.Notify(f)
+}
@@ -22,8 +22,11 @@ func (c *configurer) VisitRichApplication(ctx context.Context, cfg configkit.Ric
 func (c *configurer) VisitRichAggregate(_ context.Context, cfg configkit.RichAggregate) error {
 	c.registerController(
 		&aggregate.Controller{
-			Config:     cfg,
-			MessageIDs: &c.engine.messageIDs,
+			Config:            cfg,
+			MessageIDs:        &c.engine.messageIDs,
+			CheckDeterminism:  c.options.checkAggregateDeterminism,
+			CheckImmutability: c.options.checkMessageImmutability,
+			RootCodec:         c.options.rootCodec,
 		},
 	)
 	return nil
@@ -34,6 +37,7 @@ func (c *configurer) VisitRichProcess(_ context.Context, cfg configkit.RichProce
 		&process.Controller{
 			Config:     cfg,
 			MessageIDs: &c.engine.messageIDs,
+			RootCodec:  c.options.rootCodec,
 		},
 	)
 	return nil
@@ -2,12 +2,18 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
 
 	"github.com/dogmatiq/configkit"
 	"github.com/dogmatiq/cosyne"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/engine/internal/panicx"
+	"github.com/dogmatiq/testkit/engine/internal/process"
 	"github.com/dogmatiq/testkit/envelope"
 	"github.com/dogmatiq/testkit/fact"
 	"github.com/dogmatiq/testkit/internal/validation"
@@ -26,6 +32,30 @@ type Engine struct {
 	controllers map[string]controller
 	routes      map[message.Type][]controller
 	resetters   []func()
+
+	// dispatchLimit and causationDepthLimit are the limits set via
+	// WithDispatchLimit() and WithCausationDepthLimit(), or zero if there is
+	// no limit.
+	dispatchLimit       int
+	causationDepthLimit int
+
+	// tickLimit is the maximum number of ticks that may be performed by a
+	// single call to TickUntil().
+	tickLimit int
+
+	// checkMessageImmutability is true if handlers are checked for
+	// modifications to the messages that are passed to them.
+	checkMessageImmutability bool
+
+	// messageCodec is the codec used to marshal and unmarshal each message
+	// before it is dispatched, or nil if messages are not marshaled.
+	messageCodec Codec
+
+	// random is the source of randomness used to order handlers, or nil if
+	// handler order randomization is disabled. It is reset to its initial
+	// state, as determined by seed, when the engine is reset.
+	random *rand.Rand
+	seed   uint64
 }
 
 // New returns a new engine that uses the given app configuration.
@@ -36,6 +66,18 @@ func New(app configkit.RichApplication, options ...Option) (_ *Engine, err error
 		controllers: map[string]controller{},
 		routes:      map[message.Type][]controller{},
 		resetters:   eo.resetters,
+
+		dispatchLimit:       eo.dispatchLimit,
+		causationDepthLimit: eo.causationDepthLimit,
+		tickLimit:           eo.tickLimit,
+
+		checkMessageImmutability: eo.checkMessageImmutability,
+		messageCodec:             eo.messageCodec,
+	}
+
+	if eo.randomizeHandlerOrder {
+		e.seed = eo.seed
+		e.random = newRandom(eo.seed)
 	}
 
 	cfgr := &configurer{
@@ -70,6 +112,10 @@ func (e *Engine) Reset() {
 
 	e.messageIDs.Reset()
 
+	if e.random != nil {
+		e.random = newRandom(e.seed)
+	}
+
 	for _, c := range e.controllers {
 		c.Reset()
 	}
@@ -114,16 +160,130 @@ func (e *Engine) Tick(
 	return err
 }
 
+// TickUntil performs a "tick" of the engine at the scheduled time of each
+// pending timeout that is due to occur at or before t, in order, followed by a
+// final tick at time t.
+//
+// Unlike a single call to Tick() at time t, this causes each timeout to be
+// handled with an engine time equal to its scheduled time. Timeouts that are
+// scheduled while handling another timeout are also handled at their own
+// scheduled time, provided it is not after t.
+//
+// Timeouts that are scheduled to occur before the engine's current time, as
+// set by an engine.WithCurrentTime() option, are handled at the current time.
+// Otherwise, any engine.WithCurrentTime() option is overridden.
+//
+// It returns an error if the engine's tick limit is exceeded before reaching
+// time t.
+func (e *Engine) TickUntil(
+	ctx context.Context,
+	t time.Time,
+	options ...OperationOption,
+) error {
+	oo := newOperationOptions(e, options)
+
+	var (
+		err  error
+		prev = oo.now
+	)
+
+	for n := 0; ; n++ {
+		deadline, ok, lerr := e.nextDeadline(ctx, oo, t)
+		if lerr != nil {
+			return multierr.Append(err, lerr)
+		}
+
+		if !ok {
+			break
+		}
+
+		if n >= e.tickLimit {
+			return multierr.Append(
+				err,
+				fmt.Errorf(
+					"advancing time aborted after %d ticks, the tick limit of %d was exceeded with a timeout pending at %s",
+					n,
+					e.tickLimit,
+					deadline.Format(time.RFC3339Nano),
+				),
+			)
+		}
+
+		// Never step backwards, timeouts scheduled in the past are handled
+		// at the time of the most recent tick.
+		if deadline.Before(prev) {
+			deadline = prev
+		}
+		prev = deadline
+
+		err = multierr.Append(
+			err,
+			e.Tick(
+				ctx,
+				append(options, WithCurrentTime(deadline))...,
+			),
+		)
+
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+	}
+
+	return multierr.Append(
+		err,
+		e.Tick(
+			ctx,
+			append(options, WithCurrentTime(t))...,
+		),
+	)
+}
+
+// nextDeadline returns the earliest time, at or before t, at which a pending
+// timeout is scheduled to occur within a process that is not skipped. ok is
+// false if there is no such timeout.
+func (e *Engine) nextDeadline(
+	ctx context.Context,
+	oo *operationOptions,
+	t time.Time,
+) (deadline time.Time, ok bool, err error) {
+	if err := e.m.Lock(ctx); err != nil {
+		return time.Time{}, false, err
+	}
+	defer e.m.Unlock()
+
+	for _, c := range e.controllers {
+		pc, isProcess := c.(*process.Controller)
+		if !isProcess {
+			continue
+		}
+
+		if skip, _ := e.skipHandler(pc.HandlerConfig(), oo); skip {
+			continue
+		}
+
+		d, pending := pc.NextDeadline()
+		if !pending || d.After(t) {
+			continue
+		}
+
+		if !ok || d.Before(deadline) {
+			deadline, ok = d, true
+		}
+	}
+
+	return deadline, ok, nil
+}
+
 func (e *Engine) tick(
 	ctx context.Context,
 	oo *operationOptions,
 ) error {
 	var (
-		err   error
-		queue []*envelope.Envelope
+		err      error
+		produced [][]*envelope.Envelope
 	)
 
-	for _, c := range e.controllers {
+	for _, c := range e.tickOrder() {
 		if skip, reason := e.skipHandler(c.HandlerConfig(), oo); skip {
 			oo.observers.Notify(
 				fact.TickSkipped{
@@ -142,7 +302,7 @@ func (e *Engine) tick(
 		)
 
 		envs, cerr := c.Tick(ctx, oo.observers, oo.now)
-		queue = append(queue, envs...)
+		produced = append(produced, envs)
 
 		if cerr != nil {
 			err = multierr.Append(
@@ -173,7 +333,7 @@ func (e *Engine) tick(
 		e.dispatch(
 			ctx,
 			oo,
-			queue...,
+			e.interleave(produced)...,
 		),
 	)
 }
@@ -186,29 +346,8 @@ func (e *Engine) Dispatch(
 	m dogma.Message,
 	options ...OperationOption,
 ) error {
-	mt := message.TypeOf(m)
-	id := e.messageIDs.Next()
 	oo := newOperationOptions(e, options)
-
-	env, err := message.MapByKindOfWithErr(
-		m,
-		func(m dogma.Command) (*envelope.Envelope, error) {
-			return envelope.NewCommand(id, m, oo.now),
-				m.Validate(validation.CommandValidationScope())
-		},
-		func(m dogma.Event) (*envelope.Envelope, error) {
-			return envelope.NewEvent(id, m, oo.now),
-				m.Validate(validation.EventValidationScope())
-		},
-		nil,
-	)
-	if err != nil {
-		panic(fmt.Sprintf("cannot dispatch invalid %s message: %s", mt, err))
-	}
-
-	if _, ok := e.routes[mt]; !ok {
-		panic(fmt.Sprintf("the %s message type is not consumed by any handlers", mt))
-	}
+	env := e.newEnvelope(m, oo)
 
 	oo.observers.Notify(
 		fact.DispatchCycleBegun{
@@ -219,7 +358,7 @@ func (e *Engine) Dispatch(
 		},
 	)
 
-	err = e.m.Lock(ctx)
+	err := e.m.Lock(ctx)
 	if err == nil {
 		defer e.m.Unlock()
 		err = e.dispatch(ctx, oo, env)
@@ -237,69 +376,199 @@ func (e *Engine) Dispatch(
 	return err
 }
 
+// newEnvelope returns a new envelope containing m, which is a message that is
+// passed to the engine directly, rather than produced by a handler.
+//
+// It panics if m is a [dogma.Timeout], or is otherwise invalid.
+func (e *Engine) newEnvelope(
+	m dogma.Message,
+	oo *operationOptions,
+) *envelope.Envelope {
+	mt := message.TypeOf(m)
+	id := e.messageIDs.Next()
+
+	env, err := message.MapByKindOfWithErr(
+		m,
+		func(m dogma.Command) (*envelope.Envelope, error) {
+			return envelope.NewCommand(id, m, oo.now),
+				m.Validate(validation.CommandValidationScope())
+		},
+		func(m dogma.Event) (*envelope.Envelope, error) {
+			return envelope.NewEvent(id, m, oo.now),
+				m.Validate(validation.EventValidationScope())
+		},
+		nil,
+	)
+	if err != nil {
+		panic(fmt.Sprintf("cannot dispatch invalid %s message: %s", mt, err))
+	}
+
+	if _, ok := e.routes[mt]; !ok {
+		panic(fmt.Sprintf("the %s message type is not consumed by any handlers", mt))
+	}
+
+	return env
+}
+
 func (e *Engine) dispatch(
 	ctx context.Context,
 	oo *operationOptions,
 	queue ...*envelope.Envelope,
 ) error {
-	var err error
-
-	for len(queue) > 0 {
-		env := queue[0]
-		queue = queue[1:]
+	var (
+		err   error
+		chain = newCausationChain()
+	)
 
-		var controllers []controller
+	for n := 0; len(queue) > 0; n++ {
+		produced, ok, derr := e.dispatchEnvelope(ctx, oo, chain, n, queue[0])
+		queue = append(queue[1:], produced...)
+		err = multierr.Append(err, derr)
 
-		mt := message.TypeOf(env.Message)
+		if !ok {
+			return err
+		}
 
-		if mt.Kind() == message.TimeoutKind {
-			// always dispatch timeouts back to their origin handler
-			controllers = []controller{
-				e.controllers[env.Origin.Handler.Identity().Name],
-			}
-		} else {
-			controllers = e.routes[mt]
+		if e := ctx.Err(); e != nil {
+			return e
 		}
+	}
+
+	return err
+}
+
+// dispatchEnvelope passes env to the handlers that it is routed to and returns
+// the messages that they produce.
+//
+// n is the number of messages that have already been dispatched within chain.
+// It returns false if dispatching env would exceed the engine's limits, in
+// which case env is not dispatched and no further messages may be dispatched
+// within chain.
+func (e *Engine) dispatchEnvelope(
+	ctx context.Context,
+	oo *operationOptions,
+	chain *causationChain,
+	n int,
+	env *envelope.Envelope,
+) ([]*envelope.Envelope, bool, error) {
+	depth := chain.Add(env)
+
+	if e.exceedsLimits(n, depth) {
+		cycle := chain.Cycle(env)
+		err := e.dispatchLimitExceeded(n, depth, cycle)
 
 		oo.observers.Notify(
-			fact.DispatchBegun{
-				Envelope: env,
+			fact.DispatchLimitExceeded{
+				Envelope:   env,
+				Dispatched: n,
+				Depth:      depth,
+				Cycle:      cycle,
+				Error:      err,
 			},
 		)
 
-		var derr error
-		for _, c := range controllers {
-			envs, cerr := e.handle(ctx, oo, env, c)
-			queue = append(queue, envs...)
-
-			if cerr != nil {
-				derr = multierr.Append(
-					derr,
-					fmt.Errorf(
-						"%s %s: %w",
-						c.HandlerConfig().Identity().Name,
-						c.HandlerConfig().HandlerType(),
-						cerr,
-					),
-				)
-			}
+		return nil, false, err
+	}
+
+	if e.messageCodec != nil {
+		x, err := e.roundTripMessage(env)
+		if err != nil {
+			return nil, true, err
 		}
 
-		oo.observers.Notify(
-			fact.DispatchCompleted{
-				Envelope: env,
-				Error:    derr,
-			},
-		)
+		env = x
+	}
 
-		err = multierr.Append(err, derr)
+	var controllers []controller
 
-		if e := ctx.Err(); e != nil {
-			return e
+	mt := message.TypeOf(env.Message)
+
+	if mt.Kind() == message.TimeoutKind {
+		// always dispatch timeouts back to their origin handler
+		controllers = []controller{
+			e.controllers[env.Origin.Handler.Identity().Name],
 		}
+	} else {
+		controllers = e.routes[mt]
 	}
 
-	return err
+	oo.observers.Notify(
+		fact.DispatchBegun{
+			Envelope: env,
+		},
+	)
+
+	var (
+		err      error
+		produced [][]*envelope.Envelope
+	)
+
+	for _, r := range e.handleAll(ctx, oo, env, e.order(controllers)) {
+		produced = append(produced, r.Envelopes)
+
+		if r.Error != nil {
+			err = multierr.Append(
+				err,
+				fmt.Errorf(
+					"%s %s: %w",
+					r.Controller.HandlerConfig().Identity().Name,
+					r.Controller.HandlerConfig().HandlerType(),
+					r.Error,
+				),
+			)
+		}
+	}
+
+	oo.observers.Notify(
+		fact.DispatchCompleted{
+			Envelope: env,
+			Error:    err,
+		},
+	)
+
+	return e.interleave(produced), true, err
+}
+
+// exceedsLimits returns true if dispatching a message with the given causation
+// depth, after n other messages have already been dispatched, would exceed the
+// engine's dispatch or causation depth limit.
+func (e *Engine) exceedsLimits(n, depth int) bool {
+	if e.dispatchLimit != 0 && n >= e.dispatchLimit {
+		return true
+	}
+
+	return e.causationDepthLimit != 0 && depth > e.causationDepthLimit
+}
+
+// dispatchLimitExceeded returns the error returned by dispatch() when n
+// messages have been dispatched and the next message, with the given
+// causation depth, would exceed the engine's limits.
+func (e *Engine) dispatchLimitExceeded(
+	n, depth int,
+	cycle []message.Type,
+) error {
+	var reason string
+	if e.causationDepthLimit != 0 && depth > e.causationDepthLimit {
+		reason = fmt.Sprintf("the causation depth limit of %d was exceeded", e.causationDepthLimit)
+	} else {
+		reason = fmt.Sprintf("the dispatch limit of %d messages was exceeded", e.dispatchLimit)
+	}
+
+	if len(cycle) == 0 {
+		return fmt.Errorf("dispatch aborted after %d messages, %s", n, reason)
+	}
+
+	var types []string
+	for _, mt := range cycle {
+		types = append(types, mt.String())
+	}
+
+	return fmt.Errorf(
+		"dispatch aborted after %d messages, %s, the messages appear to be caused by a loop: %s",
+		n,
+		reason,
+		strings.Join(types, " -> "),
+	)
 }
 
 func (e *Engine) handle(
@@ -327,7 +596,7 @@ func (e *Engine) handle(
 		},
 	)
 
-	envs, err := c.Handle(ctx, oo.observers, oo.now, env)
+	envs, err := e.invoke(ctx, oo, env, c)
 
 	oo.observers.Notify(
 		fact.HandlingCompleted{
@@ -340,6 +609,55 @@ func (e *Engine) handle(
 	return envs, err
 }
 
+// invoke passes env to c for handling.
+//
+// If recovery from unexpected behavior is enabled, panics that carry a
+// panicx.UnexpectedBehavior value are converted to an error.
+//
+// If message immutability checks are enabled, c is passed a copy of env, and
+// invoke panics if the handler modifies the message.
+func (e *Engine) invoke(
+	ctx context.Context,
+	oo *operationOptions,
+	env *envelope.Envelope,
+	c controller,
+) (envs []*envelope.Envelope, err error) {
+	if oo.recoverUnexpectedBehavior {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			x, ok := r.(panicx.UnexpectedBehavior)
+			if !ok {
+				panic(r)
+			}
+
+			oo.observers.Notify(
+				fact.UnexpectedBehaviorRecovered{
+					Handler:        c.HandlerConfig(),
+					Envelope:       env,
+					Interface:      x.Interface,
+					Method:         x.Method,
+					Implementation: x.Implementation,
+					Description:    x.Description,
+					Location:       x.Location,
+				},
+			)
+
+			envs = nil
+			err = errors.New(x.String())
+		}()
+	}
+
+	if e.checkMessageImmutability {
+		return handleImmutably(ctx, oo.observers, oo.now, env, c)
+	}
+
+	return c.Handle(ctx, oo.observers, oo.now, env)
+}
+
 // skipHandler returns true if a specific handler should be skipped during a
 // call to Dispatch() or Tick().
 func (e *Engine) skipHandler(
@@ -3,6 +3,8 @@ package engine_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -295,6 +297,47 @@ var _ = g.Describe("type Engine", func() {
 			gm.Expect(err).To(gm.MatchError("<integration> integration: <error>"))
 		})
 
+		g.It("recovers from unexpected behavior if enabled", func() {
+			aggregate.RouteCommandToInstanceFunc = func(dogma.Command) string {
+				return ""
+			}
+
+			buf := &fact.Buffer{}
+			err := engine.Dispatch(
+				context.Background(),
+				AggregateCommand{},
+				WithObserver(buf),
+				RecoverUnexpectedBehavior(true),
+			)
+			gm.Expect(err).To(gm.MatchError(
+				"<aggregate> aggregate: the '<aggregate>' aggregate message handler behaved unexpectedly in *stubs.AggregateMessageHandlerStub.RouteCommandToInstance(): routed a command of type stubs.CommandStub[TypeA] to an empty ID",
+			))
+
+			var recovered []fact.UnexpectedBehaviorRecovered
+			for _, f := range buf.Facts() {
+				if x, ok := f.(fact.UnexpectedBehaviorRecovered); ok {
+					recovered = append(recovered, x)
+				}
+			}
+
+			gm.Expect(recovered).To(gm.HaveLen(1))
+			gm.Expect(recovered[0].Handler).To(gm.Equal(config.RichHandlers().Aggregates()[0]))
+			gm.Expect(recovered[0].Method).To(gm.Equal("RouteCommandToInstance"))
+			gm.Expect(recovered[0].Description).To(gm.Equal(
+				"routed a command of type stubs.CommandStub[TypeA] to an empty ID",
+			))
+		})
+
+		g.It("does not recover from unexpected behavior by default", func() {
+			aggregate.RouteCommandToInstanceFunc = func(dogma.Command) string {
+				return ""
+			}
+
+			gm.Expect(func() {
+				engine.Dispatch(context.Background(), AggregateCommand{})
+			}).To(gm.Panic())
+		})
+
 		g.It("panics if the message is invalid", func() {
 			gm.Expect(func() {
 				engine.Dispatch(
@@ -313,6 +356,541 @@ var _ = g.Describe("type Engine", func() {
 		})
 	})
 
+	g.When("message immutability checks are enabled", func() {
+		type MutableCommand = CommandStub[[]string]
+
+		g.BeforeEach(func() {
+			integration.ConfigureFunc = func(c dogma.IntegrationConfigurer) {
+				c.Identity("<integration>", "8b840c55-0b04-4107-bd4c-c69052c9fca3")
+				c.Routes(
+					dogma.HandlesCommand[MutableCommand](),
+				)
+			}
+
+			config = configkit.FromApplication(app)
+			engine = MustNew(config, EnableMessageImmutabilityChecks(true))
+		})
+
+		g.It("panics if a handler modifies the message", func() {
+			integration.HandleCommandFunc = func(
+				_ context.Context,
+				_ dogma.IntegrationCommandScope,
+				c dogma.Command,
+			) error {
+				c.(MutableCommand).Content[0] = "<modified>"
+				return nil
+			}
+
+			gm.Expect(func() {
+				engine.Dispatch(
+					context.Background(),
+					MutableCommand{Content: []string{"<original>"}},
+				)
+			}).To(gm.PanicWith(gm.WithTransform(
+				func(x any) string { return fmt.Sprint(x) },
+				gm.Equal("the '<integration>' integration message handler behaved unexpectedly in *stubs.IntegrationMessageHandlerStub.HandleCommand(): modified the command message of type stubs.CommandStub[[]string] that was passed to it"),
+			)))
+		})
+
+		g.It("does not modify the original message", func() {
+			integration.HandleCommandFunc = func(
+				_ context.Context,
+				_ dogma.IntegrationCommandScope,
+				c dogma.Command,
+			) error {
+				c.(MutableCommand).Content[0] = "<modified>"
+				return nil
+			}
+
+			m := MutableCommand{Content: []string{"<original>"}}
+
+			err := engine.Dispatch(
+				context.Background(),
+				m,
+				RecoverUnexpectedBehavior(true),
+			)
+			gm.Expect(err).To(gm.HaveOccurred())
+			gm.Expect(m.Content).To(gm.Equal([]string{"<original>"}))
+		})
+
+		g.It("does not panic if the handler does not modify the message", func() {
+			err := engine.Dispatch(
+				context.Background(),
+				MutableCommand{Content: []string{"<original>"}},
+			)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		})
+
+		g.It("panics if an aggregate root modifies an event from the instance's history", func() {
+			type MutableEvent = EventStub[map[string]int]
+
+			mutate := false
+			aggregate.ConfigureFunc = func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "c72c106b-771e-42f8-b3e6-05452d4002ed")
+				c.Routes(
+					dogma.HandlesCommand[AggregateCommand](),
+					dogma.RecordsEvent[MutableEvent](),
+				)
+			}
+			aggregate.NewFunc = func() dogma.AggregateRoot {
+				return &AggregateRootStub{
+					ApplyEventFunc: func(e dogma.Event) {
+						if mutate {
+							e.(MutableEvent).Content["<key>"]++
+						}
+					},
+				}
+			}
+			aggregate.HandleCommandFunc = func(
+				_ dogma.AggregateRoot,
+				s dogma.AggregateCommandScope,
+				_ dogma.Command,
+			) {
+				s.RecordEvent(MutableEvent{Content: map[string]int{"<key>": 1}})
+			}
+
+			config = configkit.FromApplication(app)
+			engine = MustNew(config, EnableMessageImmutabilityChecks(true))
+
+			err := engine.Dispatch(context.Background(), AggregateCommand{})
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			mutate = true
+
+			gm.Expect(func() {
+				engine.AggregateRoot(context.Background(), "<aggregate>", "<instance>")
+			}).To(gm.PanicWith(gm.WithTransform(
+				func(x any) string { return fmt.Sprint(x) },
+				gm.Equal("the '<aggregate>' aggregate message handler behaved unexpectedly in *stubs.AggregateRootStub.ApplyEvent(): modified the event message of type stubs.EventStub[map[string]int] that was passed to it"),
+			)))
+
+			history, err := engine.AggregateHistory(context.Background(), "<aggregate>", "<instance>")
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(history).To(gm.HaveLen(1))
+			gm.Expect(history[0].Message).To(gm.Equal(
+				MutableEvent{Content: map[string]int{"<key>": 1}},
+			))
+		})
+
+		g.It("panics if an aggregate root modifies an event as it is recorded", func() {
+			type MutableEvent = EventStub[map[string]int]
+
+			aggregate.ConfigureFunc = func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "c72c106b-771e-42f8-b3e6-05452d4002ed")
+				c.Routes(
+					dogma.HandlesCommand[AggregateCommand](),
+					dogma.RecordsEvent[MutableEvent](),
+				)
+			}
+			aggregate.NewFunc = func() dogma.AggregateRoot {
+				return &AggregateRootStub{
+					ApplyEventFunc: func(e dogma.Event) {
+						e.(MutableEvent).Content["<key>"]++
+					},
+				}
+			}
+			aggregate.HandleCommandFunc = func(
+				_ dogma.AggregateRoot,
+				s dogma.AggregateCommandScope,
+				_ dogma.Command,
+			) {
+				s.RecordEvent(MutableEvent{Content: map[string]int{"<key>": 1}})
+			}
+
+			config = configkit.FromApplication(app)
+			engine = MustNew(config, EnableMessageImmutabilityChecks(true))
+
+			gm.Expect(func() {
+				engine.Dispatch(context.Background(), AggregateCommand{})
+			}).To(gm.PanicWith(gm.WithTransform(
+				func(x any) string { return fmt.Sprint(x) },
+				gm.Equal("the '<aggregate>' aggregate message handler behaved unexpectedly in *stubs.AggregateRootStub.ApplyEvent(): modified the event message of type stubs.EventStub[map[string]int] that was passed to it"),
+			)))
+		})
+	})
+
+	g.When("a message codec is used", func() {
+		type LossyCommand = CommandStub[any]
+
+		g.BeforeEach(func() {
+			integration.ConfigureFunc = func(c dogma.IntegrationConfigurer) {
+				c.Identity("<integration>", "8b840c55-0b04-4107-bd4c-c69052c9fca3")
+				c.Routes(
+					dogma.HandlesCommand[LossyCommand](),
+				)
+			}
+
+			config = configkit.FromApplication(app)
+			engine = MustNew(config, WithMessageCodec(JSONCodec))
+		})
+
+		g.It("passes the unmarshaled message to the handlers", func() {
+			called := false
+			integration.HandleCommandFunc = func(
+				_ context.Context,
+				_ dogma.IntegrationCommandScope,
+				c dogma.Command,
+			) error {
+				called = true
+				gm.Expect(c).To(gm.Equal(LossyCommand{Content: "<content>"}))
+				return nil
+			}
+
+			err := engine.Dispatch(
+				context.Background(),
+				LossyCommand{Content: "<content>"},
+			)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(called).To(gm.BeTrue())
+		})
+
+		g.It("returns an error with a diff if the message does not survive the round trip", func() {
+			integration.HandleCommandFunc = func(
+				context.Context,
+				dogma.IntegrationCommandScope,
+				dogma.Command,
+			) error {
+				g.Fail("unexpected call")
+				return nil
+			}
+
+			err := engine.Dispatch(
+				context.Background(),
+				LossyCommand{Content: int32(123)}, // unmarshaled as a float64
+			)
+			gm.Expect(err).To(gm.MatchError(
+				"the stubs.CommandStub[interface {}] command does not survive being marshaled and unmarshaled: stubs.CommandStub[interface {}].Content differs after unmarshaling\n\n" +
+					"stubs.CommandStub[any]{\n" +
+					"    Content:         [-in-]{+floa+}t[-32-]{+64+}(123)\n" +
+					"    ValidationError: \"\"\n" +
+					"}",
+			))
+		})
+	})
+
+	g.When("the gob message codec is used", func() {
+		type ListCommand = CommandStub[[]string]
+
+		g.BeforeEach(func() {
+			integration.ConfigureFunc = func(c dogma.IntegrationConfigurer) {
+				c.Identity("<integration>", "8b840c55-0b04-4107-bd4c-c69052c9fca3")
+				c.Routes(
+					dogma.HandlesCommand[ListCommand](),
+				)
+			}
+
+			config = configkit.FromApplication(app)
+			engine = MustNew(config, WithMessageCodec(GobCodec))
+		})
+
+		g.It("accepts messages that contain empty collections", func() {
+			called := false
+			integration.HandleCommandFunc = func(
+				context.Context,
+				dogma.IntegrationCommandScope,
+				dogma.Command,
+			) error {
+				called = true
+				return nil
+			}
+
+			// The gob encoding does not distinguish between nil and empty
+			// slices, so the message's content is nil once unmarshaled.
+			err := engine.Dispatch(
+				context.Background(),
+				ListCommand{Content: []string{}},
+			)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(called).To(gm.BeTrue())
+		})
+	})
+
+	g.When("handler order randomization is enabled", func() {
+		var order []string
+
+		g.BeforeEach(func() {
+			order = nil
+
+			process.HandleEventFunc = func(
+				context.Context,
+				dogma.ProcessRoot,
+				dogma.ProcessEventScope,
+				dogma.Event,
+			) error {
+				order = append(order, "<process>")
+				return nil
+			}
+
+			projection.HandleEventFunc = func(
+				context.Context,
+				[]byte,
+				[]byte,
+				[]byte,
+				dogma.ProjectionEventScope,
+				dogma.Event,
+			) (bool, error) {
+				order = append(order, "<projection>")
+				return true, nil
+			}
+		})
+
+		// dispatch dispatches an event that is handled by both the process and
+		// the projection many times, and returns the order in which the
+		// handlers were invoked.
+		dispatch := func(e *Engine) []string {
+			order = nil
+
+			for range 20 {
+				err := e.Dispatch(context.Background(), AggregateEvent{})
+				gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			}
+
+			return order
+		}
+
+		g.It("invokes the handlers in the same order when using the same seed", func() {
+			a := dispatch(MustNew(config, RandomizeHandlerOrder(1)))
+			b := dispatch(MustNew(config, RandomizeHandlerOrder(1)))
+			gm.Expect(a).To(gm.Equal(b))
+		})
+
+		g.It("invokes the handlers in a different order when using a different seed", func() {
+			a := dispatch(MustNew(config, RandomizeHandlerOrder(1)))
+			b := dispatch(MustNew(config, RandomizeHandlerOrder(2)))
+			gm.Expect(a).NotTo(gm.Equal(b))
+		})
+
+		g.It("invokes the handlers in the same order after the engine is reset", func() {
+			e := MustNew(config, RandomizeHandlerOrder(1))
+
+			a := dispatch(e)
+			e.Reset()
+			b := dispatch(e)
+
+			gm.Expect(a).To(gm.Equal(b))
+		})
+
+		g.It("preserves the order of the messages produced by each handler", func() {
+			aggregate.HandleCommandFunc = func(
+				_ dogma.AggregateRoot,
+				s dogma.AggregateCommandScope,
+				_ dogma.Command,
+			) {
+				s.RecordEvent(AggregateEvent{Content: "<first>"})
+				s.RecordEvent(AggregateEvent{Content: "<second>"})
+			}
+
+			var events []dogma.Event
+			projection.HandleEventFunc = func(
+				_ context.Context,
+				_ []byte,
+				_ []byte,
+				_ []byte,
+				_ dogma.ProjectionEventScope,
+				e dogma.Event,
+			) (bool, error) {
+				events = append(events, e)
+				return true, nil
+			}
+
+			for seed := range uint64(10) {
+				events = nil
+
+				err := MustNew(config, RandomizeHandlerOrder(seed)).Dispatch(
+					context.Background(),
+					AggregateCommand{},
+				)
+				gm.Expect(err).ShouldNot(gm.HaveOccurred())
+				gm.Expect(events).To(gm.Equal([]dogma.Event{
+					AggregateEvent{Content: "<first>"},
+					AggregateEvent{Content: "<second>"},
+				}))
+			}
+		})
+	})
+
+	g.When("concurrent handling is enabled", func() {
+		g.It("invokes the handlers on separate goroutines", func() {
+			processStarted := make(chan struct{})
+			projectionStarted := make(chan struct{})
+
+			// Each handler waits for the other to start, which can only
+			// succeed if they are invoked at the same time.
+			wait := func(ctx context.Context, started chan<- struct{}, other <-chan struct{}) error {
+				close(started)
+
+				select {
+				case <-other:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			process.HandleEventFunc = func(
+				ctx context.Context,
+				_ dogma.ProcessRoot,
+				_ dogma.ProcessEventScope,
+				_ dogma.Event,
+			) error {
+				return wait(ctx, processStarted, projectionStarted)
+			}
+
+			projection.HandleEventFunc = func(
+				ctx context.Context,
+				_ []byte,
+				_ []byte,
+				_ []byte,
+				_ dogma.ProjectionEventScope,
+				_ dogma.Event,
+			) (bool, error) {
+				return true, wait(ctx, projectionStarted, processStarted)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := engine.Dispatch(
+				ctx,
+				AggregateEvent{},
+				EnableConcurrentHandling(true),
+			)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		})
+
+		g.It("notifies the observers of one fact at a time", func() {
+			var (
+				active, peak atomic.Int32
+				n            int
+			)
+
+			err := engine.Dispatch(
+				context.Background(),
+				AggregateEvent{},
+				EnableConcurrentHandling(true),
+				WithObserver(
+					fact.ObserverFunc(func(fact.Fact) {
+						if v := active.Add(1); v > peak.Load() {
+							peak.Store(v)
+						}
+						defer active.Add(-1)
+
+						n++
+						time.Sleep(time.Millisecond)
+					}),
+				),
+			)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(peak.Load()).To(gm.BeNumerically("==", 1))
+			gm.Expect(n).To(gm.BeNumerically(">", 2))
+		})
+
+		g.It("panics on the calling goroutine if a handler panics", func() {
+			projection.HandleEventFunc = func(
+				context.Context,
+				[]byte,
+				[]byte,
+				[]byte,
+				dogma.ProjectionEventScope,
+				dogma.Event,
+			) (bool, error) {
+				panic("<panic>")
+			}
+
+			gm.Expect(func() {
+				engine.Dispatch(
+					context.Background(),
+					AggregateEvent{},
+					EnableConcurrentHandling(true),
+				)
+			}).To(gm.PanicWith("<panic>"))
+		})
+	})
+
+	g.When("the handlers produce messages in a loop", func() {
+		g.BeforeEach(func() {
+			aggregate.HandleCommandFunc = func(
+				_ dogma.AggregateRoot,
+				s dogma.AggregateCommandScope,
+				_ dogma.Command,
+			) {
+				s.RecordEvent(AggregateEvent{})
+			}
+
+			process.ConfigureFunc = func(c dogma.ProcessConfigurer) {
+				c.Identity("<process>", "4721492d-7fa3-4cfa-9f0f-a3cb1f95933e")
+				c.Routes(
+					dogma.HandlesEvent[AggregateEvent](),
+					dogma.ExecutesCommand[AggregateCommand](),
+				)
+			}
+
+			process.HandleEventFunc = func(
+				_ context.Context,
+				_ dogma.ProcessRoot,
+				s dogma.ProcessEventScope,
+				_ dogma.Event,
+			) error {
+				s.ExecuteCommand(AggregateCommand{})
+				return nil
+			}
+
+			config = configkit.FromApplication(app)
+		})
+
+		g.It("returns an error if the dispatch limit is exceeded", func() {
+			engine = MustNew(config, WithDispatchLimit(5))
+
+			err := engine.Dispatch(context.Background(), AggregateCommand{})
+			gm.Expect(err).To(gm.MatchError(
+				"dispatch aborted after 5 messages, the dispatch limit of 5 messages was exceeded, the messages appear to be caused by a loop: stubs.EventStub[TypeA] -> stubs.CommandStub[TypeA] -> stubs.EventStub[TypeA]",
+			))
+		})
+
+		g.It("returns an error if the causation depth limit is exceeded", func() {
+			engine = MustNew(config, WithCausationDepthLimit(3))
+
+			err := engine.Dispatch(context.Background(), AggregateCommand{})
+			gm.Expect(err).To(gm.MatchError(
+				"dispatch aborted after 3 messages, the causation depth limit of 3 was exceeded, the messages appear to be caused by a loop: stubs.EventStub[TypeA] -> stubs.CommandStub[TypeA] -> stubs.EventStub[TypeA]",
+			))
+		})
+
+		g.It("notifies observers when a limit is exceeded", func() {
+			engine = MustNew(config, WithCausationDepthLimit(3))
+
+			buf := &fact.Buffer{}
+			err := engine.Dispatch(
+				context.Background(),
+				AggregateCommand{},
+				WithObserver(buf),
+			)
+
+			var exceeded []fact.DispatchLimitExceeded
+			for _, f := range buf.Facts() {
+				if x, ok := f.(fact.DispatchLimitExceeded); ok {
+					exceeded = append(exceeded, x)
+				}
+			}
+
+			gm.Expect(exceeded).To(gm.HaveLen(1))
+			gm.Expect(exceeded[0].Envelope.Message).To(gm.Equal(AggregateEvent{}))
+			gm.Expect(exceeded[0].Dispatched).To(gm.Equal(3))
+			gm.Expect(exceeded[0].Depth).To(gm.Equal(4))
+			gm.Expect(exceeded[0].Cycle).To(gm.HaveLen(3))
+			gm.Expect(exceeded[0].Error).To(gm.Equal(err))
+		})
+
+		g.It("does not report a loop if the message types do not repeat", func() {
+			engine = MustNew(config, WithCausationDepthLimit(1))
+
+			err := engine.Dispatch(context.Background(), AggregateCommand{})
+			gm.Expect(err).To(gm.MatchError(
+				"dispatch aborted after 1 messages, the causation depth limit of 1 was exceeded",
+			))
+		})
+	})
+
 	g.Describe("func Tick()", func() {
 		g.It("skips handlers that are disabled by type", func() {
 			buf := &fact.Buffer{}
@@ -447,4 +1025,36 @@ var _ = g.Describe("type Engine", func() {
 			gm.Expect(err).To(gm.MatchError("<projection> projection: <error>"))
 		})
 	})
+
+	g.Describe("func TickUntil()", func() {
+		g.It("performs a final tick at the given time", func() {
+			buf := &fact.Buffer{}
+			t := time.Now().Add(1 * time.Hour)
+
+			err := engine.TickUntil(
+				context.Background(),
+				t,
+				WithObserver(buf),
+				WithCurrentTime(time.Now()),
+			)
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			var times []time.Time
+			for _, f := range buf.Facts() {
+				if x, ok := f.(fact.TickCycleBegun); ok {
+					times = append(times, x.EngineTime)
+				}
+			}
+
+			gm.Expect(times).To(gm.Equal([]time.Time{t}))
+		})
+
+		g.It("returns an error if the context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := engine.TickUntil(ctx, time.Now())
+			gm.Expect(err).To(gm.Equal(context.Canceled))
+		})
+	})
 })
@@ -37,15 +37,189 @@ func EnableProjectionCompactionDuringHandling(enabled bool) Option {
 	})
 }
 
+// WithDispatchLimit returns an engine option that limits the number of
+// messages that may be dispatched by a single call to Engine.Dispatch() or
+// Engine.Tick().
+//
+// If the limit is exceeded the engine stops dispatching messages and returns
+// an error. This prevents handlers that produce messages indefinitely from
+// causing the engine to block forever. A limit of zero means there is no
+// limit, which is the default.
+func WithDispatchLimit(n int) Option {
+	if n < 0 {
+		panic("n must not be negative")
+	}
+
+	return optionFunc(func(eo *engineOptions) {
+		eo.dispatchLimit = n
+	})
+}
+
+// WithCausationDepthLimit returns an engine option that limits the length of
+// the chain of messages that may be caused by a single message passed to
+// Engine.Dispatch(), or produced by Engine.Tick().
+//
+// If the limit is exceeded the engine stops dispatching messages and returns
+// an error that describes the sequence of message types that is repeating
+// within the causation chain, if any. A limit of zero means there is no limit,
+// which is the default.
+func WithCausationDepthLimit(n int) Option {
+	if n < 0 {
+		panic("n must not be negative")
+	}
+
+	return optionFunc(func(eo *engineOptions) {
+		eo.causationDepthLimit = n
+	})
+}
+
+// WithTickLimit returns an engine option that limits the number of ticks that
+// may be performed by a single call to Engine.TickUntil().
+//
+// If the limit is exceeded the engine stops ticking and returns an error. This
+// prevents processes that repeatedly schedule timeouts in the near future from
+// causing the engine to block forever. The default limit is 10,000 ticks.
+func WithTickLimit(n int) Option {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+
+	return optionFunc(func(eo *engineOptions) {
+		eo.tickLimit = n
+	})
+}
+
+// EnableMessageImmutabilityChecks returns an engine option that causes the
+// engine to verify that handlers do not modify the messages that are passed to
+// them.
+//
+// When enabled, each handler is passed a deep copy of the message. If the copy
+// has been modified once the handler returns the engine panics with a value
+// that describes the handler and method that modified it. The comparison uses
+// the same semantics as testkit.DefaultMessageComparator.
+//
+// Modifying a message is particularly harmful when the message is an event,
+// as the same value is retained within the engine's aggregate history. As
+// such, aggregate roots are also passed a deep copy of each event that is
+// applied to them, and the engine panics if the root modifies it.
+//
+// Checks are disabled by default.
+func EnableMessageImmutabilityChecks(enabled bool) Option {
+	return optionFunc(func(eo *engineOptions) {
+		eo.checkMessageImmutability = enabled
+	})
+}
+
+// EnableAggregateDeterminismChecks returns an engine option that causes the
+// engine to verify that aggregate message handlers only modify their aggregate
+// roots by recording events.
+//
+// When enabled, after each call to HandleCommand() the engine rebuilds the
+// aggregate root from the instance's history and compares it to the root that
+// was passed to HandleCommand(). If they differ the engine panics with a value
+// that includes a diff of the two roots.
+//
+// A handler that modifies the root directly appears to work while handling a
+// single command, but the modification is lost when the root is next rebuilt
+// from its history.
+//
+// Checks are disabled by default.
+func EnableAggregateDeterminismChecks(enabled bool) Option {
+	return optionFunc(func(eo *engineOptions) {
+		eo.checkAggregateDeterminism = enabled
+	})
+}
+
+// WithRootCodec returns an engine option that causes the engine to verify that
+// aggregate and process roots survive being marshaled and unmarshaled by c.
+//
+// Process roots are marshaled and unmarshaled after each message is handled,
+// and the unmarshaled copy is used when handling the next message, as though
+// it were loaded from persistent storage. Aggregate roots are marshaled and
+// unmarshaled after each command is handled, as though a snapshot were taken.
+//
+// If a root can not be marshaled or unmarshaled, or the unmarshaled copy is
+// not equal to the original, the engine panics with a value that identifies
+// the field that did not survive.
+//
+// By default roots are kept in memory without being marshaled.
+func WithRootCodec(c Codec) Option {
+	if c == nil {
+		panic("codec must not be nil")
+	}
+
+	return optionFunc(func(eo *engineOptions) {
+		eo.rootCodec = c
+	})
+}
+
+// WithMessageCodec returns an engine option that causes the engine to marshal
+// and unmarshal each message using c before it is dispatched to its handlers.
+//
+// This includes messages passed to Engine.Dispatch() and messages produced by
+// handlers. The handlers are passed the unmarshaled copy of the message.
+//
+// If a message can not be marshaled or unmarshaled, or the unmarshaled copy is
+// not equal to the original, the message is not dispatched and the engine
+// returns an error that includes a diff of the two messages.
+//
+// By default messages are not marshaled.
+func WithMessageCodec(c Codec) Option {
+	if c == nil {
+		panic("codec must not be nil")
+	}
+
+	return optionFunc(func(eo *engineOptions) {
+		eo.messageCodec = c
+	})
+}
+
+// RandomizeHandlerOrder returns an engine option that causes the engine to
+// invoke handlers in a random order, as determined by seed.
+//
+// The handlers of each message are invoked in a random order, as are the
+// handlers during each tick. The messages produced by different handlers are
+// interleaved randomly, although the messages produced by any one handler
+// are always dispatched in the order they were produced.
+//
+// This exposes code that inadvertently depends on the order in which handlers
+// are invoked. Using the same seed for the same sequence of operations results
+// in the same order. The order is reset when the engine is reset.
+//
+// By default the handlers of each message are invoked in the order that they
+// are registered with the application.
+func RandomizeHandlerOrder(seed uint64) Option {
+	return optionFunc(func(eo *engineOptions) {
+		eo.randomizeHandlerOrder = true
+		eo.seed = seed
+	})
+}
+
+// defaultTickLimit is the number of ticks that may be performed by a single
+// call to Engine.TickUntil() if no WithTickLimit() option is given.
+const defaultTickLimit = 10_000
+
 // engineOptions is a container for the options set via Option values.
 type engineOptions struct {
 	resetters             []func()
 	compactDuringHandling bool
+	dispatchLimit         int
+	causationDepthLimit   int
+	tickLimit             int
+
+	checkMessageImmutability  bool
+	checkAggregateDeterminism bool
+	rootCodec                 Codec
+	messageCodec              Codec
+	randomizeHandlerOrder     bool
+	seed                      uint64
 }
 
 // newEngineOptions returns a new engineOptions with the given options.
 func newEngineOptions(options []Option) *engineOptions {
-	eo := &engineOptions{}
+	eo := &engineOptions{
+		tickLimit: defaultTickLimit,
+	}
 
 	for _, opt := range options {
 		opt.applyEngineOption(eo)
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/engine/internal/panicx"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/location"
+	"google.golang.org/protobuf/proto"
+)
+
+// handleImmutably passes a copy of env to c for handling, and panics if the
+// handler modifies the message within that copy.
+func handleImmutably(
+	ctx context.Context,
+	obs fact.Observer,
+	now time.Time,
+	env *envelope.Envelope,
+	c controller,
+) ([]*envelope.Envelope, error) {
+	cp := env.Clone()
+	envs, err := c.Handle(ctx, obs, now, cp)
+
+	if !messagesEqual(cp.Message, env.Message) {
+		cfg := c.HandlerConfig()
+		impl, iface, method := handlerMethod(cfg, message.KindOf(env.Message))
+
+		panic(panicx.UnexpectedBehavior{
+			Handler:        cfg,
+			Interface:      iface,
+			Method:         method,
+			Implementation: impl,
+			Message:        env.Message,
+			Description: fmt.Sprintf(
+				"modified the %s message of type %s that was passed to it",
+				message.KindOf(env.Message),
+				message.TypeOf(env.Message),
+			),
+			Location: location.OfMethod(impl, method),
+		})
+	}
+
+	return envs, err
+}
+
+// messagesEqual returns true if a and b are equal, using the same semantics as
+// testkit.DefaultMessageComparator.
+func messagesEqual(a, b dogma.Message) bool {
+	if pa, ok := a.(proto.Message); ok {
+		if pb, ok := b.(proto.Message); ok {
+			return proto.Equal(pa, pb)
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// handlerMethod returns the handler implementation described by cfg, along
+// with the names of the interface and method that are used to pass it a
+// message of kind k.
+func handlerMethod(
+	cfg configkit.RichHandler,
+	k message.Kind,
+) (impl any, iface, method string) {
+	switch cfg := cfg.(type) {
+	case configkit.RichAggregate:
+		return cfg.Handler(), "AggregateMessageHandler", "HandleCommand"
+	case configkit.RichProcess:
+		if k == message.TimeoutKind {
+			return cfg.Handler(), "ProcessMessageHandler", "HandleTimeout"
+		}
+		return cfg.Handler(), "ProcessMessageHandler", "HandleEvent"
+	case configkit.RichIntegration:
+		return cfg.Handler(), "IntegrationMessageHandler", "HandleCommand"
+	case configkit.RichProjection:
+		return cfg.Handler(), "ProjectionMessageHandler", "HandleEvent"
+	}
+
+	panic("unrecognized handler type")
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/testkit/engine/internal/aggregate"
+	"github.com/dogmatiq/testkit/engine/internal/process"
+	"github.com/dogmatiq/testkit/envelope"
+)
+
+// AggregateRoot returns the root of an aggregate instance.
+//
+// The root is rebuilt by applying the instance's history to a new root, and
+// hence modifying it has no effect on the engine's state. ok is false if the
+// instance does not exist.
+//
+// It panics if the application does not have an aggregate message handler
+// named handler.
+func (e *Engine) AggregateRoot(
+	ctx context.Context,
+	handler, id string,
+) (r dogma.AggregateRoot, ok bool, err error) {
+	c := e.aggregateController(handler)
+
+	if err := e.m.Lock(ctx); err != nil {
+		return nil, false, err
+	}
+	defer e.m.Unlock()
+
+	r, ok = c.Root(id)
+	return r, ok, nil
+}
+
+// AggregateHistory returns the envelopes containing the events recorded by an
+// aggregate instance, in the order they were recorded.
+//
+// The envelopes are copies, and hence modifying them has no effect on the
+// engine's state. It returns an empty slice if the instance does not exist.
+//
+// It panics if the application does not have an aggregate message handler
+// named handler.
+func (e *Engine) AggregateHistory(
+	ctx context.Context,
+	handler, id string,
+) ([]*envelope.Envelope, error) {
+	c := e.aggregateController(handler)
+
+	if err := e.m.Lock(ctx); err != nil {
+		return nil, err
+	}
+	defer e.m.Unlock()
+
+	return c.History(id), nil
+}
+
+// ProcessRoot returns a copy of the root of a process instance.
+//
+// Modifying the root has no effect on the engine's state. ok is false if the
+// instance does not exist.
+//
+// It panics if the application does not have a process message handler named
+// handler.
+func (e *Engine) ProcessRoot(
+	ctx context.Context,
+	handler, id string,
+) (r dogma.ProcessRoot, ok bool, err error) {
+	c := e.processController(handler)
+
+	if err := e.m.Lock(ctx); err != nil {
+		return nil, false, err
+	}
+	defer e.m.Unlock()
+
+	r, ok = c.Root(id)
+	return r, ok, nil
+}
+
+// ListInstances returns the IDs of the instances of an aggregate or process
+// that currently exist, in lexicographical order.
+//
+// It panics if the application does not have an aggregate or process message
+// handler named handler.
+func (e *Engine) ListInstances(
+	ctx context.Context,
+	handler string,
+) ([]string, error) {
+	var list func() []string
+
+	switch c := e.controllers[handler].(type) {
+	case *aggregate.Controller:
+		list = c.Instances
+	case *process.Controller:
+		list = c.Instances
+	default:
+		panic(fmt.Sprintf("the application does not have an aggregate or process named %q", handler))
+	}
+
+	if err := e.m.Lock(ctx); err != nil {
+		return nil, err
+	}
+	defer e.m.Unlock()
+
+	return list(), nil
+}
+
+// PendingTimeouts returns the envelopes containing the timeouts that have been
+// scheduled by a process but not yet handled, in the order they are scheduled
+// to occur.
+//
+// The envelopes are copies, and hence modifying them has no effect on the
+// engine's state.
+//
+// It panics if the application does not have a process message handler named
+// handler.
+func (e *Engine) PendingTimeouts(
+	ctx context.Context,
+	handler string,
+) ([]*envelope.Envelope, error) {
+	c := e.processController(handler)
+
+	if err := e.m.Lock(ctx); err != nil {
+		return nil, err
+	}
+	defer e.m.Unlock()
+
+	return c.PendingTimeouts(), nil
+}
+
+// aggregateController returns the controller for the aggregate message handler
+// named n. It panics if there is no such handler.
+func (e *Engine) aggregateController(n string) *aggregate.Controller {
+	c, ok := e.controllers[n].(*aggregate.Controller)
+	if !ok {
+		panic(fmt.Sprintf("the application does not have an aggregate named %q", n))
+	}
+
+	return c
+}
+
+// processController returns the controller for the process message handler
+// named n. It panics if there is no such handler.
+func (e *Engine) processController(n string) *process.Controller {
+	c, ok := e.controllers[n].(*process.Controller)
+	if !ok {
+		panic(fmt.Sprintf("the application does not have a process named %q", n))
+	}
+
+	return c
+}
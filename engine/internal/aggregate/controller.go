@@ -3,14 +3,22 @@ package aggregate
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dapper"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 	"github.com/dogmatiq/testkit/engine/internal/panicx"
+	"github.com/dogmatiq/testkit/engine/internal/roundtrip"
 	"github.com/dogmatiq/testkit/envelope"
 	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/clone"
+	"github.com/dogmatiq/testkit/internal/report"
 	"github.com/dogmatiq/testkit/location"
 )
 
@@ -20,6 +28,22 @@ type Controller struct {
 	Config     configkit.RichAggregate
 	MessageIDs *envelope.MessageIDGenerator
 
+	// CheckDeterminism, if true, causes the controller to verify that the
+	// root modified by each call to HandleCommand() is equal to a root that is
+	// rebuilt from the instance's history.
+	CheckDeterminism bool
+
+	// RootCodec, if non-nil, is used to verify that the aggregate root can be
+	// marshaled and unmarshaled after each call to HandleCommand(), in the
+	// same manner as a real engine that persists snapshots of its roots.
+	RootCodec roundtrip.Codec
+
+	// CheckImmutability, if true, causes the controller to pass a copy of
+	// each event to the root's ApplyEvent() method, and to panic if the root
+	// modifies it. This prevents the events in the instance's history from
+	// being modified.
+	CheckImmutability bool
+
 	history map[string][]*envelope.Envelope
 }
 
@@ -78,34 +102,10 @@ func (c *Controller) Handle(
 	}
 
 	history, exists := c.history[id]
-	r := c.Config.Handler().New()
-	if r == nil {
-		panic(panicx.UnexpectedBehavior{
-			Handler:        c.Config,
-			Interface:      "AggregateMessageHandler",
-			Method:         "New",
-			Implementation: c.Config.Handler(),
-			Message:        env.Message,
-			Description:    "returned a nil AggregateRoot",
-			Location:       location.OfMethod(c.Config.Handler(), "New"),
-		})
-	}
+	r := c.newRoot(env.Message)
 
 	if exists {
-		for _, env := range history {
-			panicx.EnrichUnexpectedMessage(
-				c.Config,
-				"AggregateRoot",
-				"ApplyEvent",
-				r,
-				env.Message,
-				func() {
-					r.ApplyEvent(
-						env.Message.(dogma.Event),
-					)
-				},
-			)
-		}
+		c.apply(r, history)
 
 		obs.Notify(fact.AggregateInstanceLoaded{
 			Handler:    c.Config,
@@ -122,14 +122,15 @@ func (c *Controller) Handle(
 	}
 
 	s := &scope{
-		instanceID: id,
-		config:     c.Config,
-		messageIDs: c.MessageIDs,
-		observer:   obs,
-		now:        now,
-		root:       r,
-		exists:     exists,
-		command:    env,
+		instanceID:        id,
+		config:            c.Config,
+		messageIDs:        c.MessageIDs,
+		observer:          obs,
+		now:               now,
+		root:              r,
+		exists:            exists,
+		command:           env,
+		checkImmutability: c.CheckImmutability,
 	}
 
 	panicx.EnrichUnexpectedMessage(
@@ -147,6 +148,17 @@ func (c *Controller) Handle(
 		},
 	)
 
+	// A root that is destroyed and then re-created by the same command is
+	// not rebuilt from the instance's entire history, so it can not be
+	// checked.
+	if c.CheckDeterminism && s.exists && !s.reverted {
+		c.checkDeterminism(env, s.root, slices.Concat(history, s.events))
+	}
+
+	if c.RootCodec != nil && s.exists {
+		c.checkSnapshot(env, s.root)
+	}
+
 	if s.exists {
 		if c.history == nil {
 			c.history = map[string][]*envelope.Envelope{}
@@ -159,6 +171,214 @@ func (c *Controller) Handle(
 	return s.events, nil
 }
 
+// checkDeterminism panics if r is not equal to a new root with history
+// applied, which indicates that the handler modified r directly, instead of
+// by recording events.
+func (c *Controller) checkDeterminism(
+	env *envelope.Envelope,
+	r dogma.AggregateRoot,
+	history []*envelope.Envelope,
+) {
+	rebuilt := c.newRoot(env.Message)
+	c.apply(rebuilt, history)
+
+	if reflect.DeepEqual(r, rebuilt) {
+		return
+	}
+
+	p := dapper.NewPrinter(
+		dapper.WithPackagePaths(false),
+	)
+
+	var diff strings.Builder
+	report.WriteDiff(
+		&diff,
+		p.Format(rebuilt),
+		p.Format(r),
+	)
+
+	panic(panicx.UnexpectedBehavior{
+		Handler:        c.Config,
+		Interface:      "AggregateMessageHandler",
+		Method:         "HandleCommand",
+		Implementation: c.Config.Handler(),
+		Message:        env.Message,
+		Description: fmt.Sprintf(
+			"modified the aggregate root without recording an event, it differs from the root rebuilt from the instance's history: %s",
+			diff.String(),
+		),
+		Location: location.OfMethod(c.Config.Handler(), "HandleCommand"),
+	})
+}
+
+// checkSnapshot panics if r does not survive being marshaled and unmarshaled
+// using c.RootCodec.
+func (c *Controller) checkSnapshot(env *envelope.Envelope, r dogma.AggregateRoot) {
+	if _, err := roundtrip.Value(c.RootCodec, r); err != nil {
+		panic(panicx.UnexpectedBehavior{
+			Handler:        c.Config,
+			Interface:      "AggregateMessageHandler",
+			Method:         "HandleCommand",
+			Implementation: c.Config.Handler(),
+			Message:        env.Message,
+			Description:    fmt.Sprintf("produced an aggregate root that does not survive being marshaled and unmarshaled: %s", err),
+			Location:       location.OfMethod(c.Config.Handler(), "HandleCommand"),
+		})
+	}
+}
+
+// Seed appends events to the history of the instance with the given ID without
+// invoking the handler's HandleCommand() method.
+func (c *Controller) Seed(
+	obs fact.Observer,
+	now time.Time,
+	id string,
+	events []dogma.Event,
+) {
+	envs := make([]*envelope.Envelope, 0, len(events))
+
+	for _, m := range events {
+		env := envelope.NewEvent(c.MessageIDs.Next(), m, now)
+		env.Origin = &envelope.Origin{
+			Handler:     c.Config,
+			HandlerType: configkit.AggregateHandlerType,
+			InstanceID:  id,
+		}
+
+		envs = append(envs, env)
+	}
+
+	if c.history == nil {
+		c.history = map[string][]*envelope.Envelope{}
+	}
+	c.history[id] = append(c.history[id], envs...)
+
+	r := c.newRoot(nil)
+	c.apply(r, c.history[id])
+
+	obs.Notify(fact.AggregateHistorySeeded{
+		Handler:        c.Config,
+		InstanceID:     id,
+		Root:           r,
+		EventEnvelopes: envs,
+	})
+}
+
+// Root returns a new aggregate root for the instance with the given ID, with
+// its history applied. ok is false if the instance does not exist.
+func (c *Controller) Root(id string) (r dogma.AggregateRoot, ok bool) {
+	history, ok := c.history[id]
+	if !ok {
+		return nil, false
+	}
+
+	r = c.newRoot(nil)
+	c.apply(r, history)
+
+	return r, true
+}
+
+// History returns copies of the envelopes containing the events that have been
+// recorded by the instance with the given ID, in the order they were recorded.
+func (c *Controller) History(id string) []*envelope.Envelope {
+	var envs []*envelope.Envelope
+	for _, env := range c.history[id] {
+		envs = append(envs, env.Clone())
+	}
+
+	return envs
+}
+
+// Instances returns the IDs of the instances that currently exist, in
+// lexicographical order.
+func (c *Controller) Instances() []string {
+	ids := make([]string, 0, len(c.history))
+	for id := range c.history {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// newRoot returns a new aggregate root. m is the message being handled, if
+// any.
+func (c *Controller) newRoot(m dogma.Message) dogma.AggregateRoot {
+	r := c.Config.Handler().New()
+	if r == nil {
+		panic(panicx.UnexpectedBehavior{
+			Handler:        c.Config,
+			Interface:      "AggregateMessageHandler",
+			Method:         "New",
+			Implementation: c.Config.Handler(),
+			Message:        m,
+			Description:    "returned a nil AggregateRoot",
+			Location:       location.OfMethod(c.Config.Handler(), "New"),
+		})
+	}
+
+	return r
+}
+
+// apply applies the events in history to r.
+func (c *Controller) apply(r dogma.AggregateRoot, history []*envelope.Envelope) {
+	for _, env := range history {
+		applyEvent(
+			c.Config,
+			r,
+			env.Message.(dogma.Event),
+			c.CheckImmutability,
+		)
+	}
+}
+
+// applyEvent applies the event m to r.
+//
+// If check is true, r is passed a copy of m, and applyEvent panics if r
+// modifies that copy.
+func applyEvent(
+	cfg configkit.RichAggregate,
+	r dogma.AggregateRoot,
+	m dogma.Event,
+	check bool,
+) {
+	e := m
+	if check {
+		e = clone.Value(m)
+	}
+
+	panicx.EnrichUnexpectedMessage(
+		cfg,
+		"AggregateRoot",
+		"ApplyEvent",
+		r,
+		m,
+		func() {
+			r.ApplyEvent(e)
+		},
+	)
+
+	if !check {
+		return
+	}
+
+	if _, ok := roundtrip.Difference(m, e); ok {
+		panic(panicx.UnexpectedBehavior{
+			Handler:        cfg,
+			Interface:      "AggregateRoot",
+			Method:         "ApplyEvent",
+			Implementation: r,
+			Message:        m,
+			Description: fmt.Sprintf(
+				"modified the event message of type %s that was passed to it",
+				message.TypeOf(m),
+			),
+			Location: location.OfMethod(r, "ApplyEvent"),
+		})
+	}
+}
+
 // Reset clears the state of the controller.
 func (c *Controller) Reset() {
 	c.history = nil
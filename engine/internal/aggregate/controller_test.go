@@ -2,6 +2,7 @@ package aggregate_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -336,6 +337,168 @@ var _ = g.Describe("type Controller", func() {
 			})
 		})
 
+		g.When("determinism checks are enabled", func() {
+			g.BeforeEach(func() {
+				ctrl.CheckDeterminism = true
+			})
+
+			g.It("panics if the handler modifies the root directly", func() {
+				handler.HandleCommandFunc = func(
+					r dogma.AggregateRoot,
+					s dogma.AggregateCommandScope,
+					_ dogma.Command,
+				) {
+					s.RecordEvent(EventA1)
+
+					x := r.(*AggregateRootStub)
+					x.AppliedEvents = append(x.AppliedEvents, EventA2)
+				}
+
+				gm.Expect(func() {
+					ctrl.Handle(
+						context.Background(),
+						fact.Ignore,
+						time.Now(),
+						command,
+					)
+				}).To(gm.PanicWith(
+					MatchAllFields(
+						Fields{
+							"Handler":        gm.Equal(config),
+							"Interface":      gm.Equal("AggregateMessageHandler"),
+							"Method":         gm.Equal("HandleCommand"),
+							"Implementation": gm.Equal(config.Handler()),
+							"Message":        gm.Equal(command.Message),
+							"Description": gm.And(
+								gm.HavePrefix("modified the aggregate root without recording an event, it differs from the root rebuilt from the instance's history: "),
+								gm.ContainSubstring("{+"),
+							),
+							"Location": MatchAllFields(
+								Fields{
+									"Func": gm.Not(gm.BeEmpty()),
+									"File": gm.HaveSuffix("/stubs/aggregate.go"), // from dogmatiq/enginekit module
+									"Line": gm.Not(gm.BeZero()),
+								},
+							),
+						},
+					),
+				))
+			})
+
+			g.It("does not panic if the handler only modifies the root by recording events", func() {
+				handler.HandleCommandFunc = func(
+					_ dogma.AggregateRoot,
+					s dogma.AggregateCommandScope,
+					_ dogma.Command,
+				) {
+					s.RecordEvent(EventA1)
+				}
+
+				for range 2 {
+					_, err := ctrl.Handle(
+						context.Background(),
+						fact.Ignore,
+						time.Now(),
+						command,
+					)
+					gm.Expect(err).ShouldNot(gm.HaveOccurred())
+				}
+			})
+
+			g.It("does not record the events if the handler modifies the root directly", func() {
+				handler.HandleCommandFunc = func(
+					r dogma.AggregateRoot,
+					s dogma.AggregateCommandScope,
+					_ dogma.Command,
+				) {
+					s.RecordEvent(EventA1)
+					r.(*AggregateRootStub).AppliedEvents = nil
+				}
+
+				gm.Expect(func() {
+					ctrl.Handle(
+						context.Background(),
+						fact.Ignore,
+						time.Now(),
+						command,
+					)
+				}).To(gm.Panic())
+
+				gm.Expect(ctrl.Instances()).To(gm.BeEmpty())
+			})
+		})
+
+		g.When("a root codec is used", func() {
+			g.BeforeEach(func() {
+				ctrl.RootCodec = jsonCodec{}
+			})
+
+			g.It("panics if the root does not survive being marshaled and unmarshaled", func() {
+				handler.NewFunc = func() dogma.AggregateRoot {
+					return &counterRoot{}
+				}
+
+				handler.HandleCommandFunc = func(
+					_ dogma.AggregateRoot,
+					s dogma.AggregateCommandScope,
+					_ dogma.Command,
+				) {
+					s.RecordEvent(EventA1)
+				}
+
+				gm.Expect(func() {
+					ctrl.Handle(
+						context.Background(),
+						fact.Ignore,
+						time.Now(),
+						command,
+					)
+				}).To(gm.PanicWith(
+					MatchAllFields(
+						Fields{
+							"Handler":        gm.Equal(config),
+							"Interface":      gm.Equal("AggregateMessageHandler"),
+							"Method":         gm.Equal("HandleCommand"),
+							"Implementation": gm.Equal(config.Handler()),
+							"Message":        gm.Equal(command.Message),
+							"Description":    gm.Equal("produced an aggregate root that does not survive being marshaled and unmarshaled: *aggregate_test.counterRoot.unexported differs after unmarshaling"),
+							"Location": MatchAllFields(
+								Fields{
+									"Func": gm.Not(gm.BeEmpty()),
+									"File": gm.HaveSuffix("/stubs/aggregate.go"), // from dogmatiq/enginekit module
+									"Line": gm.Not(gm.BeZero()),
+								},
+							),
+						},
+					),
+				))
+			})
+
+			g.It("does not panic if the root survives being marshaled and unmarshaled", func() {
+				handler.NewFunc = func() dogma.AggregateRoot {
+					return &counterRoot{
+						ExportedOnly: true,
+					}
+				}
+
+				handler.HandleCommandFunc = func(
+					_ dogma.AggregateRoot,
+					s dogma.AggregateCommandScope,
+					_ dogma.Command,
+				) {
+					s.RecordEvent(EventA1)
+				}
+
+				_, err := ctrl.Handle(
+					context.Background(),
+					fact.Ignore,
+					time.Now(),
+					command,
+				)
+				gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			})
+		})
+
 		g.It("provides more context to UnexpectedMessage panics from RouteCommandToInstance()", func() {
 			handler.RouteCommandToInstanceFunc = func(dogma.Command) string {
 				panic(dogma.UnexpectedMessage)
@@ -511,3 +674,29 @@ var _ = g.Describe("type Controller", func() {
 		})
 	})
 })
+
+// counterRoot is an aggregate root that counts the events applied to it.
+type counterRoot struct {
+	ExportedOnly bool
+	Exported     int
+	unexported   int
+}
+
+func (r *counterRoot) ApplyEvent(dogma.Event) {
+	r.Exported++
+
+	if !r.ExportedOnly {
+		r.unexported++
+	}
+}
+
+// jsonCodec is a Codec that uses Go's standard JSON encoding.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
@@ -24,8 +24,13 @@ type scope struct {
 	now        time.Time
 	exists     bool
 	destroyed  bool
+	reverted   bool
 	command    *envelope.Envelope
 	events     []*envelope.Envelope
+
+	// checkImmutability is true if the root must not modify the events that
+	// are applied to it.
+	checkImmutability bool
 }
 
 func (s *scope) InstanceID() string {
@@ -78,6 +83,7 @@ func (s *scope) RecordEvent(m dogma.Event) {
 
 	if !s.exists {
 		if s.destroyed {
+			s.reverted = true
 			s.observer.Notify(fact.AggregateInstanceDestructionReverted{
 				Handler:    s.config,
 				InstanceID: s.instanceID,
@@ -97,16 +103,7 @@ func (s *scope) RecordEvent(m dogma.Event) {
 		s.destroyed = false
 	}
 
-	panicx.EnrichUnexpectedMessage(
-		s.config,
-		"AggregateRoot",
-		"ApplyEvent",
-		s.root,
-		m,
-		func() {
-			s.root.ApplyEvent(m)
-		},
-	)
+	applyEvent(s.config, s.root, m, s.checkImmutability)
 
 	env := s.command.NewEvent(
 		s.messageIDs.Next(),
@@ -10,8 +10,10 @@ import (
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 	"github.com/dogmatiq/testkit/engine/internal/panicx"
+	"github.com/dogmatiq/testkit/engine/internal/roundtrip"
 	"github.com/dogmatiq/testkit/envelope"
 	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/clone"
 	"github.com/dogmatiq/testkit/location"
 )
 
@@ -21,6 +23,11 @@ type Controller struct {
 	Config     configkit.RichProcess
 	MessageIDs *envelope.MessageIDGenerator
 
+	// RootCodec, if non-nil, is used to marshal and unmarshal the process root
+	// after each message is handled, in the same manner as a real engine that
+	// persists its process roots.
+	RootCodec roundtrip.Codec
+
 	instances map[string]dogma.ProcessRoot
 	timeouts  []*envelope.Envelope
 }
@@ -141,11 +148,83 @@ func (c *Controller) Handle(
 		return s.commands, nil
 	}
 
+	if c.RootCodec != nil {
+		s.root = c.roundTrip(s)
+	}
+
 	c.update(s)
 
 	return append(s.commands, s.ready...), nil
 }
 
+// Seed sets the root of the instance with the given ID without invoking any of
+// the handler's methods.
+func (c *Controller) Seed(
+	obs fact.Observer,
+	id string,
+	r dogma.ProcessRoot,
+) {
+	if c.instances == nil {
+		c.instances = map[string]dogma.ProcessRoot{}
+	}
+
+	// Store a copy of the root so that the caller can not modify the engine's
+	// state after seeding it.
+	c.instances[id] = clone.Value(r)
+
+	obs.Notify(fact.ProcessRootSeeded{
+		Handler:    c.Config,
+		InstanceID: id,
+		Root:       r,
+	})
+}
+
+// Root returns a copy of the root of the instance with the given ID. ok is
+// false if the instance does not exist.
+func (c *Controller) Root(id string) (r dogma.ProcessRoot, ok bool) {
+	r, ok = c.instances[id]
+	if !ok {
+		return nil, false
+	}
+
+	return clone.Value(r), true
+}
+
+// Instances returns the IDs of the instances that currently exist, in
+// lexicographical order.
+func (c *Controller) Instances() []string {
+	ids := make([]string, 0, len(c.instances))
+	for id := range c.instances {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// PendingTimeouts returns copies of the envelopes containing the timeouts that
+// have been scheduled but not yet handled, in the order they are scheduled to
+// occur.
+func (c *Controller) PendingTimeouts() []*envelope.Envelope {
+	var envs []*envelope.Envelope
+	for _, env := range c.timeouts {
+		envs = append(envs, env.Clone())
+	}
+
+	return envs
+}
+
+// NextDeadline returns the time at which the earliest pending timeout is
+// scheduled to occur. ok is false if there are no pending timeouts.
+func (c *Controller) NextDeadline() (t time.Time, ok bool) {
+	if len(c.timeouts) == 0 {
+		return time.Time{}, false
+	}
+
+	return c.timeouts[0].ScheduledFor, true
+}
+
 // Reset clears the state of the controller.
 func (c *Controller) Reset() {
 	c.instances = nil
@@ -259,6 +338,27 @@ func (c *Controller) handle(ctx context.Context, s *scope) error {
 	return err
 }
 
+// roundTrip returns a copy of the process root in s that has been marshaled
+// and unmarshaled using c.RootCodec.
+//
+// It panics if the root does not survive the round trip.
+func (c *Controller) roundTrip(s *scope) dogma.ProcessRoot {
+	r, err := roundtrip.Value(c.RootCodec, s.root)
+	if err != nil {
+		panic(panicx.UnexpectedBehavior{
+			Handler:        c.Config,
+			Interface:      "ProcessMessageHandler",
+			Method:         s.handleMethod,
+			Implementation: c.Config.Handler(),
+			Message:        s.env.Message,
+			Description:    fmt.Sprintf("produced a process root that does not survive being marshaled and unmarshaled: %s", err),
+			Location:       location.OfMethod(c.Config.Handler(), s.handleMethod),
+		})
+	}
+
+	return r
+}
+
 // update stores the process root and its pending timeouts.
 func (c *Controller) update(s *scope) {
 	if c.instances == nil {
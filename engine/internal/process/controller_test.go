@@ -2,6 +2,7 @@ package process_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -838,6 +839,82 @@ var _ = g.Describe("type Controller", func() {
 			})
 		})
 
+		g.When("a root codec is used", func() {
+			g.BeforeEach(func() {
+				ctrl.RootCodec = jsonCodec{}
+			})
+
+			g.It("passes an unmarshaled copy of the root when handling the next message", func() {
+				var previous dogma.ProcessRoot
+
+				handler.HandleEventFunc = func(
+					_ context.Context,
+					r dogma.ProcessRoot,
+					_ dogma.ProcessEventScope,
+					_ dogma.Event,
+				) error {
+					if previous != nil {
+						gm.Expect(r).To(gm.Equal(&ProcessRootStub{Value: "<value>"}))
+						gm.Expect(r).NotTo(gm.BeIdenticalTo(previous))
+					}
+
+					r.(*ProcessRootStub).Value = "<value>"
+					previous = r
+
+					return nil
+				}
+
+				for range 2 {
+					_, err := ctrl.Handle(
+						context.Background(),
+						fact.Ignore,
+						time.Now(),
+						event,
+					)
+					gm.Expect(err).ShouldNot(gm.HaveOccurred())
+				}
+			})
+
+			g.It("panics if the root does not survive being marshaled and unmarshaled", func() {
+				handler.HandleEventFunc = func(
+					_ context.Context,
+					r dogma.ProcessRoot,
+					_ dogma.ProcessEventScope,
+					_ dogma.Event,
+				) error {
+					r.(*ProcessRootStub).Value = 123 // unmarshaled as a float64
+					return nil
+				}
+
+				gm.Expect(func() {
+					ctrl.Handle(
+						context.Background(),
+						fact.Ignore,
+						time.Now(),
+						event,
+					)
+				}).To(gm.PanicWith(
+					MatchAllFields(
+						Fields{
+							"Handler":        gm.Equal(config),
+							"Interface":      gm.Equal("ProcessMessageHandler"),
+							"Method":         gm.Equal("HandleEvent"),
+							"Implementation": gm.Equal(config.Handler()),
+							"Message":        gm.Equal(event.Message),
+							"Description":    gm.Equal("produced a process root that does not survive being marshaled and unmarshaled: *stubs.ProcessRootStub.Value differs after unmarshaling"),
+							"Location": MatchAllFields(
+								Fields{
+									"Func": gm.Not(gm.BeEmpty()),
+									"File": gm.HaveSuffix("/stubs/process.go"), // from dogmatiq/enginekit module
+									"Line": gm.Not(gm.BeZero()),
+								},
+							),
+						},
+					),
+				))
+			})
+		})
+
 		g.It("provides more context to UnexpectedMessage panics from RouteEventToInstance()", func() {
 			handler.RouteEventToInstanceFunc = func(
 				context.Context,
@@ -984,3 +1061,14 @@ var _ = g.Describe("type Controller", func() {
 		})
 	})
 })
+
+// jsonCodec is a Codec that uses Go's standard JSON encoding.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
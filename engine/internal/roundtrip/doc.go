@@ -0,0 +1,3 @@
+// Package roundtrip verifies that values survive being marshaled and
+// unmarshaled, as they would be when persisted by a real engine.
+package roundtrip
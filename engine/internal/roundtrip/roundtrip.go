@@ -0,0 +1,253 @@
+package roundtrip
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec marshals and unmarshals values.
+//
+// It has the same method set as engine.Codec.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Value marshals v using c, then unmarshals the result into a new value of the
+// same type, which it returns.
+//
+// It returns an error if v can not be marshaled or unmarshaled, or if the new
+// value is not equal to v. If v is a pointer, the data is unmarshaled into a
+// pointer to a new value of the pointed-to type, otherwise it is unmarshaled
+// into a pointer to a new value of v's type.
+func Value[T any](c Codec, v T) (T, error) {
+	var zero T
+
+	data, err := c.Marshal(v)
+	if err != nil {
+		return zero, fmt.Errorf("unable to marshal %T: %w", v, err)
+	}
+
+	rt := reflect.TypeOf(v)
+
+	var target, result reflect.Value
+	if rt.Kind() == reflect.Pointer {
+		target = reflect.New(rt.Elem())
+		result = target
+	} else {
+		target = reflect.New(rt)
+		result = target.Elem()
+	}
+
+	if err := c.Unmarshal(data, target.Interface()); err != nil {
+		return zero, fmt.Errorf("unable to unmarshal %T: %w", v, err)
+	}
+
+	if path, ok := Difference(v, result.Interface()); ok {
+		return zero, DifferenceError{
+			Path:        path,
+			Original:    v,
+			Unmarshaled: result.Interface(),
+		}
+	}
+
+	return result.Interface().(T), nil
+}
+
+// DifferenceError is the error returned by [Value] when the unmarshaled value
+// is not equal to the original.
+type DifferenceError struct {
+	// Path is the path to the first value that differs, as per [Difference].
+	Path string
+
+	// Original is the value that was marshaled, and Unmarshaled is the value
+	// produced by unmarshaling it.
+	Original, Unmarshaled any
+}
+
+func (e DifferenceError) Error() string {
+	return fmt.Sprintf("%T%s differs after unmarshaling", e.Original, e.Path)
+}
+
+var (
+	protoMessageType = reflect.TypeFor[proto.Message]()
+	timeType         = reflect.TypeFor[time.Time]()
+)
+
+// Difference returns the path to the first value within a and b that differs,
+// such as ".Items[2].Name". ok is false if a and b are equal. The path is
+// empty if a and b themselves differ.
+//
+// Unlike [reflect.DeepEqual], time values are compared using
+// [time.Time.Equal], protocol buffers messages are compared using
+// [proto.Equal], and nil slices and maps are equal to empty ones, as many
+// codecs do not distinguish between them.
+func Difference(a, b any) (path string, ok bool) {
+	c := &comparer{
+		seen: map[[2]uintptr]struct{}{},
+	}
+
+	return c.compare(
+		reflect.ValueOf(a),
+		reflect.ValueOf(b),
+		"",
+	)
+}
+
+// comparer finds the first difference between two values.
+type comparer struct {
+	seen map[[2]uintptr]struct{}
+}
+
+func (c *comparer) compare(a, b reflect.Value, path string) (string, bool) {
+	if a.IsValid() != b.IsValid() {
+		return path, true
+	}
+
+	if !a.IsValid() {
+		return "", false
+	}
+
+	if a.Type() != b.Type() {
+		return path, true
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		return c.comparePointer(a, b, path)
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return path, a.IsNil() != b.IsNil()
+		}
+		return c.compare(a.Elem(), b.Elem(), path)
+	case reflect.Struct:
+		return c.compareStruct(a, b, path)
+	case reflect.Slice, reflect.Array:
+		return c.compareElements(a, b, path)
+	case reflect.Map:
+		return c.compareMap(a, b, path)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// These values can not be meaningfully marshaled, so they only
+		// survive if they are nil.
+		return path, !a.IsNil() || !b.IsNil()
+	default:
+		return path, !a.Equal(b)
+	}
+}
+
+func (c *comparer) comparePointer(a, b reflect.Value, path string) (string, bool) {
+	if a.IsNil() || b.IsNil() {
+		return path, a.IsNil() != b.IsNil()
+	}
+
+	if a.Type().Implements(protoMessageType) {
+		return path, !proto.Equal(
+			accessible(a).Interface().(proto.Message),
+			accessible(b).Interface().(proto.Message),
+		)
+	}
+
+	k := [2]uintptr{a.Pointer(), b.Pointer()}
+	if _, ok := c.seen[k]; ok {
+		return "", false
+	}
+	c.seen[k] = struct{}{}
+
+	return c.compare(a.Elem(), b.Elem(), path)
+}
+
+func (c *comparer) compareStruct(a, b reflect.Value, path string) (string, bool) {
+	a = addressable(a)
+	b = addressable(b)
+
+	if a.Type() == timeType {
+		return path, !accessible(a).Interface().(time.Time).Equal(
+			accessible(b).Interface().(time.Time),
+		)
+	}
+
+	for i := 0; i < a.NumField(); i++ {
+		if p, ok := c.compare(
+			accessible(a.Field(i)),
+			accessible(b.Field(i)),
+			path+"."+a.Type().Field(i).Name,
+		); ok {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *comparer) compareElements(a, b reflect.Value, path string) (string, bool) {
+	if a.Len() != b.Len() {
+		return path, true
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		if p, ok := c.compare(
+			a.Index(i),
+			b.Index(i),
+			fmt.Sprintf("%s[%d]", path, i),
+		); ok {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *comparer) compareMap(a, b reflect.Value, path string) (string, bool) {
+	if a.Len() != b.Len() {
+		return path, true
+	}
+
+	iter := a.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		p := fmt.Sprintf("%s[%#v]", path, accessible(addressable(k)).Interface())
+
+		v := b.MapIndex(k)
+		if !v.IsValid() {
+			return p, true
+		}
+
+		if p, ok := c.compare(iter.Value(), v, p); ok {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// addressable returns v if it is addressable, otherwise it returns an
+// addressable copy of v.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+
+	x := reflect.New(v.Type()).Elem()
+	x.Set(v)
+
+	return x
+}
+
+// accessible returns a value that refers to the same memory as v, but which
+// can be read even if v was obtained via an unexported struct field.
+func accessible(v reflect.Value) reflect.Value {
+	if v.CanInterface() {
+		return v
+	}
+
+	v = addressable(v)
+
+	return reflect.NewAt(
+		v.Type(),
+		unsafe.Pointer(v.UnsafeAddr()),
+	).Elem()
+}
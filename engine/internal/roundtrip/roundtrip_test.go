@@ -0,0 +1,158 @@
+package roundtrip_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/dogmatiq/testkit/engine/internal/roundtrip"
+	"github.com/dogmatiq/testkit/internal/fixtures"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func Value()", func() {
+	type Inner struct {
+		Name string
+	}
+
+	type Root struct {
+		Items   []Inner
+		Lookup  map[string]int
+		Time    time.Time
+		Pointer *Inner
+	}
+
+	g.It("returns an equal copy of the value", func() {
+		v := &Root{
+			Items:   []Inner{{Name: "<name>"}},
+			Lookup:  map[string]int{"<key>": 1},
+			Time:    time.Now(), // includes a monotonic clock reading
+			Pointer: &Inner{Name: "<pointer>"},
+		}
+
+		r, err := Value(jsonCodec{}, v)
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		gm.Expect(r).NotTo(gm.BeIdenticalTo(v))
+		gm.Expect(r.Items).To(gm.Equal(v.Items))
+		gm.Expect(r.Pointer).To(gm.Equal(v.Pointer))
+	})
+
+	g.It("treats nil and empty collections as equal", func() {
+		v := &Root{
+			Items: []Inner{},
+		}
+
+		// The gob encoding does not distinguish between nil and empty slices.
+		r, err := Value(gobCodec{}, v)
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		gm.Expect(r.Items).To(gm.BeNil())
+	})
+
+	g.It("supports non-pointer values", func() {
+		r, err := Value(jsonCodec{}, Inner{Name: "<name>"})
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		gm.Expect(r).To(gm.Equal(Inner{Name: "<name>"}))
+	})
+
+	g.It("returns an error if the value can not be marshaled", func() {
+		_, err := Value(jsonCodec{}, &struct{ Func func() }{func() {}})
+		gm.Expect(err).To(gm.MatchError(gm.HavePrefix("unable to marshal *struct { Func func() }: ")))
+	})
+
+	g.It("returns an error if the value can not be unmarshaled", func() {
+		_, err := Value(jsonCodec{}, &struct{ Value json.Marshaler }{json.RawMessage(`{}`)})
+		gm.Expect(err).To(gm.MatchError(gm.HavePrefix("unable to unmarshal *struct { Value json.Marshaler }: ")))
+	})
+
+	g.It("returns an error that identifies the field that differs", func() {
+		type withUnexported struct {
+			Items []struct {
+				Name    string
+				private int
+			}
+		}
+
+		v := withUnexported{}
+		v.Items = append(v.Items, struct {
+			Name    string
+			private int
+		}{"<name>", 1})
+
+		_, err := Value(jsonCodec{}, v)
+		gm.Expect(err).To(gm.MatchError("roundtrip_test.withUnexported.Items[0].private differs after unmarshaling"))
+
+		var diffErr DifferenceError
+		gm.Expect(errors.As(err, &diffErr)).To(gm.BeTrue())
+		gm.Expect(diffErr.Path).To(gm.Equal(".Items[0].private"))
+		gm.Expect(diffErr.Original).To(gm.Equal(v))
+		gm.Expect(diffErr.Unmarshaled).To(gm.Equal(withUnexported{
+			Items: []struct {
+				Name    string
+				private int
+			}{{Name: "<name>"}},
+		}))
+	})
+})
+
+var _ = g.Describe("func Difference()", func() {
+	g.DescribeTable(
+		"it returns the path to the first difference",
+		func(a, b any, path string, ok bool) {
+			p, x := Difference(a, b)
+			gm.Expect(x).To(gm.Equal(ok))
+			gm.Expect(p).To(gm.Equal(path))
+		},
+		g.Entry("equal values", []int{1, 2}, []int{1, 2}, "", false),
+		g.Entry("unequal values", 1, 2, "", true),
+		g.Entry("different types", 1, "1", "", true),
+		g.Entry("slice element", []int{1, 2}, []int{1, 3}, "[1]", true),
+		g.Entry("slice length", []int{1, 2}, []int{1}, "", true),
+		g.Entry("map element", map[string]int{"a": 1}, map[string]int{"a": 2}, `["a"]`, true),
+		g.Entry("missing map element", map[string]int{"a": 1}, map[string]int{"b": 1}, `["a"]`, true),
+		g.Entry("nil and empty slice", []int(nil), []int{}, "", false),
+		g.Entry("nil and empty map", map[string]int(nil), map[string]int{}, "", false),
+		g.Entry("non-nil function", struct{ F func() }{func() {}}, struct{ F func() }{}, ".F", true),
+		g.Entry("nil function", struct{ F func() }{}, struct{ F func() }{}, "", false),
+		g.Entry(
+			"time values with different locations",
+			time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC),
+			time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).In(time.FixedZone("X", 0)),
+			"",
+			false,
+		),
+		g.Entry(
+			"protocol buffers messages",
+			&fixtures.ProtoMessage{Value: "<value>"},
+			&fixtures.ProtoMessage{Value: "<other>"},
+			"",
+			true,
+		),
+	)
+})
+
+// jsonCodec is a Codec that uses Go's standard JSON encoding.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// gobCodec is a Codec that uses Go's standard gob encoding.
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
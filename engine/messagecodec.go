@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dapper"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/engine/internal/roundtrip"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/internal/report"
+)
+
+// roundTripMessage returns a copy of env containing a copy of its message that
+// has been marshaled and unmarshaled using the engine's message codec.
+//
+// It returns an error if the message does not survive the round trip. If the
+// unmarshaled message differs from the original, the error includes a diff of
+// the two messages.
+func (e *Engine) roundTripMessage(env *envelope.Envelope) (*envelope.Envelope, error) {
+	m, err := roundtrip.Value(e.messageCodec, env.Message)
+	if err == nil {
+		x := *env
+		x.Message = m
+		return &x, nil
+	}
+
+	mt := message.TypeOf(env.Message)
+	desc := fmt.Sprintf(
+		"the %s %s does not survive being marshaled and unmarshaled: %s",
+		mt,
+		mt.Kind(),
+		err,
+	)
+
+	var diffErr roundtrip.DifferenceError
+	if !errors.As(err, &diffErr) {
+		return nil, errors.New(desc)
+	}
+
+	p := dapper.NewPrinter(
+		dapper.WithPackagePaths(false),
+	)
+
+	var w strings.Builder
+	w.WriteString(desc)
+	w.WriteString("\n\n")
+
+	report.WriteDiff(
+		&w,
+		p.Format(diffErr.Original),
+		p.Format(diffErr.Unmarshaled),
+	)
+
+	return nil, errors.New(w.String())
+}
@@ -106,6 +106,40 @@ func WithCurrentTime(t time.Time) OperationOption {
 	})
 }
 
+// RecoverUnexpectedBehavior returns an operation option that controls whether
+// the engine recovers from panics caused by handlers that behave in a way that
+// the engine does not expect, such as by violating the Dogma specification.
+//
+// When enabled, such panics are converted into an error that is returned by the
+// handler, and a fact.UnexpectedBehaviorRecovered fact is recorded. Otherwise,
+// the panic propagates to the caller.
+//
+// Recovery is disabled by default.
+func RecoverUnexpectedBehavior(enabled bool) OperationOption {
+	return operationOptionFunc(func(_ *Engine, oo *operationOptions) {
+		oo.recoverUnexpectedBehavior = enabled
+	})
+}
+
+// EnableConcurrentHandling returns an operation option that controls whether
+// each message is passed to its handlers concurrently.
+//
+// When enabled, each of the handlers that a message is routed to is invoked on
+// a separate goroutine. Messages are still dispatched one at a time, so each
+// aggregate or process instance never handles more than one message at once.
+// The observers are notified of one fact at a time, but facts from different
+// handlers may be interleaved.
+//
+// This allows tests run with the -race flag to detect data races between
+// handlers that share state.
+//
+// Concurrent handling is disabled by default.
+func EnableConcurrentHandling(enabled bool) OperationOption {
+	return operationOptionFunc(func(_ *Engine, oo *operationOptions) {
+		oo.concurrentHandling = enabled
+	})
+}
+
 // operationOptions is a container for the options set via OperationOption
 // values.
 type operationOptions struct {
@@ -113,6 +147,9 @@ type operationOptions struct {
 	observers           fact.ObserverGroup
 	enabledHandlerTypes map[configkit.HandlerType]bool
 	enabledHandlers     map[string]bool
+
+	recoverUnexpectedBehavior bool
+	concurrentHandling        bool
 }
 
 // newOperationOptions returns a new operationOptions with the given options.
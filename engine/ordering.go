@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/testkit/envelope"
+)
+
+// newRandom returns the source of randomness used to order handlers when
+// handler order randomization is enabled with the given seed.
+func newRandom(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// order returns controllers in the order that they should be invoked.
+//
+// If handler order randomization is disabled, controllers is returned
+// unchanged.
+func (e *Engine) order(controllers []controller) []controller {
+	if e.random == nil {
+		return controllers
+	}
+
+	// Sort the controllers first so that the order does not depend on the
+	// order in which they were registered, which in turn depends on the
+	// (unspecified) map iteration order, otherwise the order could not be
+	// reproduced from the seed.
+	controllers = slices.Clone(controllers)
+	slices.SortFunc(
+		controllers,
+		func(a, b controller) int {
+			return strings.Compare(
+				a.HandlerConfig().Identity().Name,
+				b.HandlerConfig().Identity().Name,
+			)
+		},
+	)
+
+	e.random.Shuffle(
+		len(controllers),
+		func(i, j int) {
+			controllers[i], controllers[j] = controllers[j], controllers[i]
+		},
+	)
+
+	return controllers
+}
+
+// tickOrder returns all of the engine's controllers in the order that they
+// should be ticked.
+func (e *Engine) tickOrder() []controller {
+	controllers := make([]controller, 0, len(e.controllers))
+	for _, c := range e.controllers {
+		controllers = append(controllers, c)
+	}
+
+	return e.order(controllers)
+}
+
+// interleave returns the envelopes in groups as a single slice. Each group
+// contains the envelopes produced by a single handler.
+//
+// If handler order randomization is disabled, the groups are concatenated in
+// order. Otherwise, the groups are interleaved randomly, while preserving the
+// order of the envelopes within each group.
+func (e *Engine) interleave(groups [][]*envelope.Envelope) []*envelope.Envelope {
+	var envs []*envelope.Envelope
+
+	if e.random == nil {
+		for _, g := range groups {
+			envs = append(envs, g...)
+		}
+
+		return envs
+	}
+
+	groups = slices.DeleteFunc(
+		slices.Clone(groups),
+		func(g []*envelope.Envelope) bool { return len(g) == 0 },
+	)
+
+	for len(groups) > 0 {
+		i := e.random.IntN(len(groups))
+		envs = append(envs, groups[i][0])
+
+		if groups[i] = groups[i][1:]; len(groups[i]) == 0 {
+			groups = slices.Delete(groups, i, i+1)
+		}
+	}
+
+	return envs
+}
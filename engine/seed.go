@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/internal/validation"
+)
+
+// SeedAggregateHistory appends events to the history of an aggregate instance
+// without invoking the aggregate message handler.
+//
+// It allows the instance to be placed into a particular state without
+// executing the commands that would ordinarily produce the events.
+//
+// It returns an error if any of the events are not recorded by the handler, or
+// are invalid. It panics if the application does not have an aggregate message
+// handler named handler.
+func (e *Engine) SeedAggregateHistory(
+	ctx context.Context,
+	handler, id string,
+	events []dogma.Event,
+	options ...OperationOption,
+) error {
+	c := e.aggregateController(handler)
+
+	if id == "" {
+		panic("instance ID must not be empty")
+	}
+
+	for _, m := range events {
+		mt := message.TypeOf(m)
+
+		if !c.Config.MessageTypes()[mt].IsProduced {
+			return fmt.Errorf(
+				"cannot seed the history of the '%s' aggregate with a %s event, it is not recorded by that handler",
+				handler,
+				mt,
+			)
+		}
+
+		if err := m.Validate(validation.EventValidationScope()); err != nil {
+			return fmt.Errorf(
+				"cannot seed the history of the '%s' aggregate with an invalid %s event: %w",
+				handler,
+				mt,
+				err,
+			)
+		}
+	}
+
+	oo := newOperationOptions(e, options)
+
+	if err := e.m.Lock(ctx); err != nil {
+		return err
+	}
+	defer e.m.Unlock()
+
+	c.Seed(oo.observers, oo.now, id, events)
+
+	return nil
+}
+
+// SeedProcessRoot sets the root of a process instance without invoking the
+// process message handler.
+//
+// If the instance already exists its root is replaced, but any timeouts that
+// it has scheduled remain pending.
+//
+// It panics if the application does not have a process message handler named
+// handler, or if r is nil.
+func (e *Engine) SeedProcessRoot(
+	ctx context.Context,
+	handler, id string,
+	r dogma.ProcessRoot,
+	options ...OperationOption,
+) error {
+	c := e.processController(handler)
+
+	if id == "" {
+		panic("instance ID must not be empty")
+	}
+
+	if r == nil {
+		panic("process root must not be nil")
+	}
+
+	oo := newOperationOptions(e, options)
+
+	if err := e.m.Lock(ctx); err != nil {
+		return err
+	}
+	defer e.m.Unlock()
+
+	c.Seed(oo.observers, id, r)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"slices"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/testkit/envelope"
+)
+
+// Stepper dispatches messages to an engine's handlers one at a time.
+//
+// Unlike [Engine.Dispatch], which dispatches a message and every message that
+// it causes in a single operation, a Stepper keeps a queue of pending messages
+// that the caller may inspect and modify between each step. This allows tests
+// to reorder, drop or duplicate messages in order to exercise the application
+// under the delivery conditions that a real engine may exhibit.
+//
+// The engine's dispatch and causation depth limits apply to all of the
+// messages dispatched by a Stepper.
+//
+// A Stepper is not safe for concurrent use.
+type Stepper struct {
+	engine  *Engine
+	options []OperationOption
+	chain   *causationChain
+	queue   []*envelope.Envelope
+	n       int
+}
+
+// NewStepper returns a [Stepper] that dispatches messages to e's handlers.
+//
+// The options apply to every step. Unless the [WithCurrentTime] option is
+// used, each step is performed at the current time, as read when Enqueue() or
+// Step() is called.
+func (e *Engine) NewStepper(options ...OperationOption) *Stepper {
+	return &Stepper{
+		engine:  e,
+		options: slices.Clone(options),
+		chain:   newCausationChain(),
+	}
+}
+
+// Enqueue adds a [dogma.Command] or [dogma.Event] to the end of the queue.
+//
+// It panics if the message is a [dogma.Timeout], or is otherwise invalid.
+func (s *Stepper) Enqueue(m dogma.Message) {
+	s.queue = append(
+		s.queue,
+		s.engine.newEnvelope(
+			m,
+			newOperationOptions(s.engine, s.options),
+		),
+	)
+}
+
+// Queue returns the envelopes of the messages that are waiting to be
+// dispatched, in the order that they will be dispatched.
+//
+// The returned slice is a copy, and hence modifying it does not affect the
+// queue. Use SetQueue() to replace the queue.
+func (s *Stepper) Queue() []*envelope.Envelope {
+	return slices.Clone(s.queue)
+}
+
+// SetQueue replaces the messages that are waiting to be dispatched.
+//
+// The envelopes are typically obtained from Queue(). They may be given in any
+// order, omitted entirely, or included more than once to simulate a message
+// being delivered multiple times.
+func (s *Stepper) SetQueue(queue []*envelope.Envelope) {
+	s.queue = slices.Clone(queue)
+}
+
+// Step dispatches the message at the front of the queue to its handlers, and
+// appends any messages that they produce to the end of the queue.
+//
+// It returns false if no message is dispatched, either because the queue is
+// empty or because the engine could not be locked before ctx was canceled.
+//
+// If dispatching the message would exceed the engine's limits the message is
+// not dispatched, the queue is cleared and an error is returned.
+func (s *Stepper) Step(ctx context.Context) (bool, error) {
+	if len(s.queue) == 0 {
+		return false, nil
+	}
+
+	if err := s.engine.m.Lock(ctx); err != nil {
+		// The message has not been dispatched, so it remains in the queue.
+		return false, err
+	}
+	defer s.engine.m.Unlock()
+
+	oo := newOperationOptions(s.engine, s.options)
+
+	env := s.queue[0]
+	s.queue = s.queue[1:]
+
+	produced, ok, err := s.engine.dispatchEnvelope(ctx, oo, s.chain, s.n, env)
+	s.n++
+
+	if !ok {
+		s.queue = nil
+		return true, err
+	}
+
+	s.queue = append(s.queue, produced...)
+
+	if e := ctx.Err(); e != nil {
+		return true, e
+	}
+
+	return true, err
+}
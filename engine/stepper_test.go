@@ -0,0 +1,261 @@
+package engine_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("type Stepper", func() {
+	var (
+		handled []string
+		block   func()
+		config  configkit.RichApplication
+		buf     *fact.Buffer
+		engine  *Engine
+		stepper *Stepper
+	)
+
+	g.BeforeEach(func() {
+		handled = nil
+		block = func() {}
+
+		aggregate := &AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "1a4b3f2e-5d8e-4b1f-9c0a-2d7e6f8a9b0c")
+				c.Routes(
+					dogma.HandlesCommand[CommandStub[TypeA]](),
+					dogma.RecordsEvent[EventStub[TypeA]](),
+				)
+			},
+			RouteCommandToInstanceFunc: func(dogma.Command) string {
+				return "<instance>"
+			},
+			HandleCommandFunc: func(
+				_ dogma.AggregateRoot,
+				s dogma.AggregateCommandScope,
+				m dogma.Command,
+			) {
+				block()
+
+				c := m.(CommandStub[TypeA])
+				handled = append(handled, string(c.Content))
+				s.RecordEvent(EventStub[TypeA]{Content: c.Content})
+			},
+		}
+
+		app := &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "6c1d9b7e-3f2a-4e8d-b5c4-0a9f8e7d6c5b")
+				c.RegisterAggregate(aggregate)
+			},
+		}
+
+		buf = &fact.Buffer{}
+		config = configkit.FromApplication(app)
+		engine = MustNew(config)
+		stepper = engine.NewStepper(WithObserver(buf))
+	})
+
+	// messages returns the messages in the stepper's queue.
+	messages := func() []dogma.Message {
+		var messages []dogma.Message
+		for _, env := range stepper.Queue() {
+			messages = append(messages, env.Message)
+		}
+		return messages
+	}
+
+	g.Describe("func Step()", func() {
+		g.It("dispatches one message at a time", func() {
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<first>"})
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<second>"})
+
+			ok, err := stepper.Step(context.Background())
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(ok).To(gm.BeTrue())
+			gm.Expect(handled).To(gm.Equal([]string{"<first>"}))
+
+			gm.Expect(messages()).To(gm.Equal([]dogma.Message{
+				CommandStub[TypeA]{Content: "<second>"},
+				EventStub[TypeA]{Content: "<first>"},
+			}))
+		})
+
+		g.It("returns false if the queue is empty", func() {
+			ok, err := stepper.Step(context.Background())
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+			gm.Expect(ok).To(gm.BeFalse())
+		})
+
+		g.It("notifies observers of the same facts as Dispatch()", func() {
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<command>"})
+			env := stepper.Queue()[0]
+
+			_, err := stepper.Step(context.Background())
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			gm.Expect(buf.Facts()).To(gm.ContainElement(
+				fact.DispatchBegun{
+					Envelope: env,
+				},
+			))
+			gm.Expect(buf.Facts()).To(gm.ContainElement(
+				fact.DispatchCompleted{
+					Envelope: env,
+				},
+			))
+		})
+
+		g.It("returns an error and clears the queue if the dispatch limit is exceeded", func() {
+			stepper = MustNew(
+				config,
+				WithDispatchLimit(1),
+			).NewStepper()
+
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<command>"})
+
+			_, err := stepper.Step(context.Background())
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			ok, err := stepper.Step(context.Background())
+			gm.Expect(err).To(gm.MatchError(
+				"dispatch aborted after 1 messages, the dispatch limit of 1 messages was exceeded",
+			))
+			gm.Expect(ok).To(gm.BeTrue())
+			gm.Expect(stepper.Queue()).To(gm.BeEmpty())
+		})
+
+		g.It("returns an error if the context is canceled", func() {
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<command>"})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := stepper.Step(ctx)
+			gm.Expect(err).To(gm.Equal(context.Canceled))
+		})
+
+		g.It("does not dispatch the message if the engine can not be locked", func() {
+			entered := make(chan struct{})
+			release := make(chan struct{})
+			block = func() {
+				block = func() {}
+				close(entered)
+				<-release
+			}
+
+			// Hold the engine's lock by dispatching a message that blocks
+			// within its handler.
+			result := make(chan error, 1)
+			go func() {
+				result <- engine.Dispatch(
+					context.Background(),
+					CommandStub[TypeA]{Content: "<blocking>"},
+				)
+			}()
+			<-entered
+
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<command>"})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			ok, err := stepper.Step(ctx)
+			gm.Expect(err).To(gm.Equal(context.DeadlineExceeded))
+			gm.Expect(ok).To(gm.BeFalse())
+			gm.Expect(messages()).To(gm.Equal([]dogma.Message{
+				CommandStub[TypeA]{Content: "<command>"},
+			}))
+
+			close(release)
+			gm.Expect(<-result).ShouldNot(gm.HaveOccurred())
+		})
+
+		g.It("performs each step at the current time", func() {
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<command>"})
+			now := time.Now()
+
+			_, err := stepper.Step(context.Background())
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			env := stepper.Queue()[0]
+			gm.Expect(env.CreatedAt).To(gm.BeTemporally(">=", now))
+		})
+
+		g.It("performs each step at the time given by the WithCurrentTime() option", func() {
+			now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+			stepper = engine.NewStepper(WithCurrentTime(now))
+
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<command>"})
+
+			_, err := stepper.Step(context.Background())
+			gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+			env := stepper.Queue()[0]
+			gm.Expect(env.CreatedAt).To(gm.Equal(now))
+		})
+	})
+
+	g.Describe("func SetQueue()", func() {
+		g.BeforeEach(func() {
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<first>"})
+			stepper.Enqueue(CommandStub[TypeA]{Content: "<second>"})
+		})
+
+		// drain dispatches messages until the queue is empty.
+		drain := func() {
+			for {
+				ok, err := stepper.Step(context.Background())
+				gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+				if !ok {
+					return
+				}
+			}
+		}
+
+		g.It("allows messages to be reordered", func() {
+			q := stepper.Queue()
+			stepper.SetQueue([]*envelope.Envelope{q[1], q[0]})
+			drain()
+
+			gm.Expect(handled).To(gm.Equal([]string{"<second>", "<first>"}))
+		})
+
+		g.It("allows messages to be dropped", func() {
+			q := stepper.Queue()
+			stepper.SetQueue(q[1:])
+			drain()
+
+			gm.Expect(handled).To(gm.Equal([]string{"<second>"}))
+		})
+
+		g.It("allows messages to be duplicated", func() {
+			q := stepper.Queue()
+			stepper.SetQueue([]*envelope.Envelope{q[0], q[0], q[1]})
+			drain()
+
+			gm.Expect(handled).To(gm.Equal([]string{"<first>", "<first>", "<second>"}))
+		})
+
+		g.It("does not retain the given slice", func() {
+			q := stepper.Queue()
+			stepper.SetQueue(q)
+			q[0] = q[1]
+
+			gm.Expect(messages()).To(gm.Equal([]dogma.Message{
+				CommandStub[TypeA]{Content: "<first>"},
+				CommandStub[TypeA]{Content: "<second>"},
+			}))
+		})
+	})
+})
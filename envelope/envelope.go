@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/testkit/internal/clone"
 )
 
 // Envelope is a container for a message that is handled by the test engine.
@@ -139,3 +140,19 @@ func (e *Envelope) NewTimeout(
 		Origin:        &o,
 	}
 }
+
+// Clone returns a copy of the envelope.
+//
+// The message is deep-copied, such that modifications to the message within
+// the copy do not affect the original.
+func (e *Envelope) Clone() *Envelope {
+	x := *e
+	x.Message = clone.Value(e.Message)
+
+	if e.Origin != nil {
+		o := *e.Origin
+		x.Origin = &o
+	}
+
+	return &x
+}
@@ -186,4 +186,40 @@ var _ = g.Describe("type Envelope", func() {
 			))
 		})
 	})
+
+	g.Describe("func Clone()", func() {
+		handler := configkit.FromProcess(&ProcessMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProcessConfigurer) {
+				c.Identity("<handler>", "5b0c4f7e-2a61-4d3b-8e9a-7c1f0d2e3b4a")
+				c.Routes(
+					dogma.HandlesEvent[EventStub[TypeA]](),
+					dogma.ExecutesCommand[CommandStub[TypeA]](),
+				)
+			},
+		})
+
+		g.It("returns an equivalent envelope", func() {
+			parent := NewEvent(
+				"100",
+				EventA1,
+				time.Now(),
+			)
+			env := parent.NewCommand(
+				"200",
+				CommandA1,
+				time.Now(),
+				Origin{
+					Handler:     handler,
+					HandlerType: configkit.ProcessHandlerType,
+					InstanceID:  "<instance>",
+				},
+			)
+
+			c := env.Clone()
+
+			gm.Expect(c).To(gm.Equal(env))
+			gm.Expect(c).NotTo(gm.BeIdenticalTo(env))
+			gm.Expect(c.Origin).NotTo(gm.BeIdenticalTo(env.Origin))
+		})
+	})
 })
@@ -0,0 +1,290 @@
+package testkit
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+// ToCreateAggregateInstance returns an expectation that passes if the
+// aggregate message handler named handler creates the instance with the given
+// ID.
+func ToCreateAggregateInstance(handler, id string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToCreateAggregateInstance(%#v, %#v): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("ToCreateAggregateInstance(%#v, %#v): instance ID must not be empty", handler, id))
+	}
+
+	return &aggregateInstanceExpectation{
+		handler:    handler,
+		instanceID: id,
+		change:     aggregateInstanceCreated,
+	}
+}
+
+// ToCreateAnyAggregateInstance returns an expectation that passes if the
+// aggregate message handler named handler creates any instance.
+func ToCreateAnyAggregateInstance(handler string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToCreateAnyAggregateInstance(%#v): handler name must not be empty", handler))
+	}
+
+	return &aggregateInstanceExpectation{
+		handler: handler,
+		change:  aggregateInstanceCreated,
+	}
+}
+
+// ToDestroyAggregateInstance returns an expectation that passes if the
+// aggregate message handler named handler destroys the instance with the given
+// ID.
+//
+// The expectation does not pass if the handler reverts the destruction by
+// recording another event while handling the same command.
+func ToDestroyAggregateInstance(handler, id string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToDestroyAggregateInstance(%#v, %#v): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("ToDestroyAggregateInstance(%#v, %#v): instance ID must not be empty", handler, id))
+	}
+
+	return &aggregateInstanceExpectation{
+		handler:    handler,
+		instanceID: id,
+		change:     aggregateInstanceDestroyed,
+	}
+}
+
+// ToDestroyAnyAggregateInstance returns an expectation that passes if the
+// aggregate message handler named handler destroys any instance.
+//
+// The expectation does not pass if the handler reverts the destruction by
+// recording another event while handling the same command.
+func ToDestroyAnyAggregateInstance(handler string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToDestroyAnyAggregateInstance(%#v): handler name must not be empty", handler))
+	}
+
+	return &aggregateInstanceExpectation{
+		handler: handler,
+		change:  aggregateInstanceDestroyed,
+	}
+}
+
+// The lifecycle changes of an aggregate instance, as they appear in test
+// reports.
+const (
+	aggregateInstanceLoaded              = "loaded"
+	aggregateInstanceNotFound            = "not found"
+	aggregateInstanceCreated             = "created"
+	aggregateInstanceDestroyed           = "destroyed"
+	aggregateInstanceDestructionReverted = "destruction reverted"
+)
+
+// aggregateInstanceExpectation is an Expectation that checks that an aggregate
+// instance is created or destroyed.
+//
+// It is the implementation used by ToCreateAggregateInstance(),
+// ToCreateAnyAggregateInstance(), ToDestroyAggregateInstance() and
+// ToDestroyAnyAggregateInstance().
+type aggregateInstanceExpectation struct {
+	handler    string
+	instanceID string // empty means "any instance"
+	change     string
+}
+
+func (e *aggregateInstanceExpectation) Caption() string {
+	return "to " + e.criteria()
+}
+
+func (e *aggregateInstanceExpectation) criteria() string {
+	verb := "create"
+	if e.change == aggregateInstanceDestroyed {
+		verb = "destroy"
+	}
+
+	if e.instanceID == "" {
+		return fmt.Sprintf(
+			"%s any instance of the '%s' aggregate",
+			verb,
+			e.handler,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s the '%s' instance of the '%s' aggregate",
+		verb,
+		e.instanceID,
+		e.handler,
+	)
+}
+
+func (e *aggregateInstanceExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	if err := guardAgainstExpectationOnUnknownHandler(
+		s,
+		e.handler,
+		configkit.AggregateHandlerType,
+	); err != nil {
+		return nil, err
+	}
+
+	return &aggregateInstancePredicate{
+		expectation: e,
+		tracker: instanceTracker{
+			handler: e.handler,
+		},
+	}, nil
+}
+
+// aggregateInstancePredicate is the Predicate implementation for
+// aggregateInstanceExpectation.
+type aggregateInstancePredicate struct {
+	expectation *aggregateInstanceExpectation
+	tracker     instanceTracker
+}
+
+func (p *aggregateInstancePredicate) Notify(f fact.Fact) {
+	p.tracker.Notify(f)
+
+	switch x := f.(type) {
+	case fact.AggregateInstanceLoaded:
+		p.record(x.Handler, x.InstanceID, aggregateInstanceLoaded)
+	case fact.AggregateInstanceNotFound:
+		p.record(x.Handler, x.InstanceID, aggregateInstanceNotFound)
+	case fact.AggregateInstanceCreated:
+		p.record(x.Handler, x.InstanceID, aggregateInstanceCreated)
+	case fact.AggregateInstanceDestroyed:
+		p.record(x.Handler, x.InstanceID, aggregateInstanceDestroyed)
+	case fact.AggregateInstanceDestructionReverted:
+		p.record(x.Handler, x.InstanceID, aggregateInstanceDestructionReverted)
+	}
+}
+
+func (p *aggregateInstancePredicate) record(h configkit.RichAggregate, id, change string) {
+	if h.Identity().Name == p.expectation.handler {
+		p.tracker.record(id, change)
+	}
+}
+
+func (p *aggregateInstancePredicate) Ok() bool {
+	// A destruction only counts if it was not subsequently reverted while
+	// handling the same command.
+	if p.expectation.instanceID == "" {
+		return p.tracker.underwentAny(
+			p.expectation.change,
+			aggregateInstanceDestructionReverted,
+		)
+	}
+
+	return p.tracker.underwent(
+		p.expectation.instanceID,
+		p.expectation.change,
+		aggregateInstanceDestructionReverted,
+	)
+}
+
+func (p *aggregateInstancePredicate) Done() {
+}
+
+func (p *aggregateInstancePredicate) Report(ctx ReportGenerationContext) *Report {
+	ok := p.Ok()
+
+	rep := &Report{
+		TreeOk:   ctx.TreeOk,
+		Ok:       ok,
+		Criteria: p.expectation.criteria(),
+	}
+
+	if ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if p.tracker.reportNotEngaged(rep, configkit.AggregateHandlerType) {
+		return rep
+	}
+
+	p.tracker.buildInstancesSection(rep)
+
+	if p.expectation.instanceID == "" {
+		p.explainAny(rep)
+	} else {
+		p.explainSpecific(rep)
+	}
+
+	return rep
+}
+
+// explainAny populates the report's explanation and suggestions when the
+// expectation is not met for any instance.
+func (p *aggregateInstancePredicate) explainAny(rep *Report) {
+	s := rep.Section(suggestionsSection)
+
+	if p.expectation.change == aggregateInstanceCreated {
+		rep.Explanation = "no instances were created"
+		s.AppendListItem("verify that the handler records an event when handling a command that targets a new instance")
+	} else {
+		rep.Explanation = "no instances were destroyed"
+		s.AppendListItem("verify that the handler calls AggregateCommandScope.Destroy()")
+	}
+
+	s.AppendListItem("verify the logic within the '%s' aggregate message handler", p.expectation.handler)
+}
+
+// explainSpecific populates the report's explanation and suggestions when the
+// expectation is not met for a specific instance.
+func (p *aggregateInstancePredicate) explainSpecific(rep *Report) {
+	s := rep.Section(suggestionsSection)
+	id := p.expectation.instanceID
+	others := p.tracker.others(id, p.expectation.change)
+
+	if len(others) != 0 {
+		if p.expectation.change == aggregateInstanceCreated {
+			rep.Explanation = fmt.Sprintf("a different instance (%s) was created", quoteIDs(others))
+		} else {
+			rep.Explanation = fmt.Sprintf("a different instance (%s) was destroyed", quoteIDs(others))
+		}
+
+		s.AppendListItem(
+			"verify the logic within the RouteCommandToInstance() method of the '%s' aggregate message handler",
+			p.expectation.handler,
+		)
+		return
+	}
+
+	if _, ok := p.tracker.history[id]; !ok {
+		rep.Explanation = fmt.Sprintf("the '%s' instance was not targeted by any command", id)
+		s.AppendListItem(
+			"verify the logic within the RouteCommandToInstance() method of the '%s' aggregate message handler",
+			p.expectation.handler,
+		)
+		return
+	}
+
+	switch p.expectation.change {
+	case aggregateInstanceCreated:
+		if p.tracker.has(id, aggregateInstanceLoaded) {
+			rep.Explanation = "the instance already existed"
+			s.AppendListItem("verify that the instance is not created by a prior action")
+		} else {
+			rep.Explanation = "the instance was targeted, but no events were recorded"
+			s.AppendListItem("verify the logic within the '%s' aggregate message handler", p.expectation.handler)
+		}
+	case aggregateInstanceDestroyed:
+		if p.tracker.has(id, aggregateInstanceDestructionReverted) {
+			rep.Explanation = "the instance was destroyed, but the destruction was reverted by recording a subsequent event"
+			s.AppendListItem("verify that the handler does not record events after calling AggregateCommandScope.Destroy()")
+		} else if !p.tracker.has(id, aggregateInstanceLoaded) && !p.tracker.has(id, aggregateInstanceCreated) {
+			rep.Explanation = "the instance did not exist"
+			s.AppendListItem("verify that the instance is created by a prior action")
+		} else {
+			rep.Explanation = "the instance was targeted, but it was not destroyed"
+			s.AppendListItem("verify the logic within the '%s' aggregate message handler", p.expectation.handler)
+		}
+	}
+}
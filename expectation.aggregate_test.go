@@ -0,0 +1,337 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("aggregate instance expectations", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		CommandThatCreates            = CommandStub[TypeC]
+		CommandThatDestroys           = CommandStub[TypeD]
+		CommandThatDestroysAndReverts = CommandStub[TypeR]
+		CommandThatDoesNothing        = CommandStub[TypeN]
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "c2a8a1d4-62a4-4c2b-b7d4-5a0b0c3d9e11")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "5f1bd1b0-3a8e-4d5c-8f0b-0b3bbd3c1c52")
+						c.Routes(
+							dogma.HandlesCommand[CommandThatCreates](),
+							dogma.HandlesCommand[CommandThatDestroys](),
+							dogma.HandlesCommand[CommandThatDestroysAndReverts](),
+							dogma.HandlesCommand[CommandThatDoesNothing](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(m dogma.Command) string {
+						switch m := m.(type) {
+						case CommandThatCreates:
+							return string(m.Content)
+						case CommandThatDestroys:
+							return string(m.Content)
+						case CommandThatDestroysAndReverts:
+							return string(m.Content)
+						case CommandThatDoesNothing:
+							return string(m.Content)
+						default:
+							panic(dogma.UnexpectedMessage)
+						}
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						switch m.(type) {
+						case CommandThatCreates:
+							s.RecordEvent(EventA1)
+						case CommandThatDestroys:
+							s.Destroy()
+						case CommandThatDestroysAndReverts:
+							s.Destroy()
+							s.RecordEvent(EventA1)
+						}
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "0a7b4f0e-2d7a-4b8e-9c1f-5e6d7c8b9a00")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandThatDoesNothing](),
+						)
+					},
+					RouteEventToInstanceFunc: func(
+						context.Context,
+						dogma.Event,
+					) (string, bool, error) {
+						return "", false, nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			setup []Action,
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+			options ...TestOption,
+		) {
+			test := Begin(testingT, app, options...)
+			test.Prepare(setup...)
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"specific instance created as expected",
+			nil,
+			ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			ToCreateAggregateInstance("<aggregate>", "<instance-1>"),
+			expectPass,
+			expectReport(
+				`✓ create the '<instance-1>' instance of the '<aggregate>' aggregate`,
+			),
+		),
+		g.Entry(
+			"any instance created as expected",
+			nil,
+			ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			ToCreateAnyAggregateInstance("<aggregate>"),
+			expectPass,
+			expectReport(
+				`✓ create any instance of the '<aggregate>' aggregate`,
+			),
+		),
+		g.Entry(
+			"a different instance created",
+			nil,
+			ExecuteCommand(CommandThatCreates{Content: "<instance-2>"}),
+			ToCreateAggregateInstance("<aggregate>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ create the '<instance-1>' instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a different instance ('<instance-2>') was created`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-2>: not found, created`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the RouteCommandToInstance() method of the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"instance already existed",
+			[]Action{
+				ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			},
+			ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			ToCreateAggregateInstance("<aggregate>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ create the '<instance-1>' instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance already existed`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: loaded`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the instance is not created by a prior action`,
+			),
+		),
+		g.Entry(
+			"instance targeted but not created",
+			nil,
+			ExecuteCommand(CommandThatDoesNothing{Content: "<instance-1>"}),
+			ToCreateAggregateInstance("<aggregate>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ create the '<instance-1>' instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance was targeted, but no events were recorded`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: not found`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"specific instance destroyed as expected",
+			[]Action{
+				ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			},
+			ExecuteCommand(CommandThatDestroys{Content: "<instance-1>"}),
+			ToDestroyAggregateInstance("<aggregate>", "<instance-1>"),
+			expectPass,
+			expectReport(
+				`✓ destroy the '<instance-1>' instance of the '<aggregate>' aggregate`,
+			),
+		),
+		g.Entry(
+			"any instance destroyed as expected",
+			[]Action{
+				ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			},
+			ExecuteCommand(CommandThatDestroys{Content: "<instance-1>"}),
+			ToDestroyAnyAggregateInstance("<aggregate>"),
+			expectPass,
+			expectReport(
+				`✓ destroy any instance of the '<aggregate>' aggregate`,
+			),
+		),
+		g.Entry(
+			"destruction reverted",
+			[]Action{
+				ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			},
+			ExecuteCommand(CommandThatDestroysAndReverts{Content: "<instance-1>"}),
+			ToDestroyAggregateInstance("<aggregate>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ destroy the '<instance-1>' instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance was destroyed, but the destruction was reverted by recording a subsequent event`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: loaded, destroyed, destruction reverted`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the handler does not record events after calling AggregateCommandScope.Destroy()`,
+			),
+		),
+		g.Entry(
+			"instance to be destroyed did not exist",
+			nil,
+			ExecuteCommand(CommandThatDestroys{Content: "<instance-1>"}),
+			ToDestroyAggregateInstance("<aggregate>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ destroy the '<instance-1>' instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance did not exist`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: not found`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the instance is created by a prior action`,
+			),
+		),
+		g.Entry(
+			"no instances destroyed",
+			[]Action{
+				ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			},
+			ExecuteCommand(CommandThatDoesNothing{Content: "<instance-1>"}),
+			ToDestroyAnyAggregateInstance("<aggregate>"),
+			expectFail,
+			expectReport(
+				`✗ destroy any instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     no instances were destroyed`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: loaded`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the handler calls AggregateCommandScope.Destroy()`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"handler disabled",
+			nil,
+			ExecuteCommand(CommandThatCreates{Content: "<instance-1>"}),
+			ToCreateAnyAggregateInstance("<aggregate>"),
+			expectFail,
+			expectReport(
+				`✗ create any instance of the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the '<aggregate>' aggregate message handler was not engaged`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • enable the '<aggregate>' aggregate message handler`,
+			),
+			WithUnsafeOperationOptions(
+				engine.EnableAggregates(false),
+			),
+		),
+	)
+
+	g.It("fails the test if the handler does not exist", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToCreateAnyAggregateInstance("<unknown>"),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"the '<app>' application does not have a handler named '<unknown>'",
+		))
+	})
+
+	g.It("fails the test if the handler is not an aggregate", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToDestroyAnyAggregateInstance("<process>"),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"'<process>' is a process message handler, not an aggregate message handler",
+		))
+	})
+
+	g.It("panics if the handler name is empty", func() {
+		gm.Expect(func() {
+			ToCreateAggregateInstance("", "<instance>")
+		}).To(gm.PanicWith(`ToCreateAggregateInstance("", "<instance>"): handler name must not be empty`))
+	})
+
+	g.It("panics if the instance ID is empty", func() {
+		gm.Expect(func() {
+			ToDestroyAggregateInstance("<aggregate>", "")
+		}).To(gm.PanicWith(`ToDestroyAggregateInstance("<aggregate>", ""): instance ID must not be empty`))
+	})
+})
@@ -0,0 +1,296 @@
+package testkit
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+const (
+	// matchesSection is the heading for the section of the test report that
+	// lists the messages that matched the expectation passed to Exactly(),
+	// AtLeast() or AtMost().
+	matchesSection = "Matches"
+)
+
+// Exactly is an expectation that passes if e is met exactly n times.
+//
+// e is typically an expectation that matches a single message, such as
+// ToExecuteCommand() or ToRecordEventType(). Each time e is met it is replaced
+// with a new instance that is only notified of the facts that occur
+// thereafter, such that each matching message is counted once. e may not be
+// an expectation that is met before the action is performed, such as Not() or
+// ToProduceNothing(), as there would be no way to tell how many times it was
+// met.
+//
+// Exactly(1, e) is useful for asserting that a message is produced once and
+// only once.
+func Exactly(n int, e Expectation) Expectation {
+	if e == nil {
+		panic(fmt.Sprintf("Exactly(%d, <nil>): expectation must not be nil", n))
+	}
+
+	if n < 0 {
+		panic(fmt.Sprintf("Exactly(%d, <expectation>): n must not be negative", n))
+	}
+
+	return &cardinalityExpectation{
+		name:      "Exactly",
+		qualifier: "exactly",
+		n:         n,
+		min:       n,
+		max:       n,
+		child:     e,
+	}
+}
+
+// AtLeast is an expectation that passes if e is met n or more times.
+//
+// e is counted in the same manner as Exactly().
+func AtLeast(n int, e Expectation) Expectation {
+	if e == nil {
+		panic(fmt.Sprintf("AtLeast(%d, <nil>): expectation must not be nil", n))
+	}
+
+	if n < 1 {
+		panic(fmt.Sprintf("AtLeast(%d, <expectation>): n must be 1 or greater", n))
+	}
+
+	return &cardinalityExpectation{
+		name:      "AtLeast",
+		qualifier: "at least",
+		n:         n,
+		min:       n,
+		max:       -1,
+		child:     e,
+	}
+}
+
+// AtMost is an expectation that passes if e is met no more than n times,
+// including not at all.
+//
+// e is counted in the same manner as Exactly().
+func AtMost(n int, e Expectation) Expectation {
+	if e == nil {
+		panic(fmt.Sprintf("AtMost(%d, <nil>): expectation must not be nil", n))
+	}
+
+	if n < 0 {
+		panic(fmt.Sprintf("AtMost(%d, <expectation>): n must not be negative", n))
+	}
+
+	return &cardinalityExpectation{
+		name:      "AtMost",
+		qualifier: "at most",
+		n:         n,
+		min:       0,
+		max:       n,
+		child:     e,
+	}
+}
+
+// cardinalityExpectation is an Expectation that checks that another
+// expectation is met a specific number of times.
+//
+// It is the implementation used by Exactly(), AtLeast() and AtMost().
+type cardinalityExpectation struct {
+	name      string
+	qualifier string
+	n         int
+	min, max  int // max is negative if there is no upper bound
+	child     Expectation
+}
+
+func (e *cardinalityExpectation) Caption() string {
+	return fmt.Sprintf(
+		"%s %s %s",
+		e.child.Caption(),
+		e.qualifier,
+		times(e.n),
+	)
+}
+
+func (e *cardinalityExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	p, err := e.child.Predicate(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Ok() {
+		return nil, fmt.Errorf(
+			"%s(%d, <expectation>): the expectation (%s) is met before the action is performed, so it can not be counted",
+			e.name,
+			e.n,
+			e.child.Caption(),
+		)
+	}
+
+	return &cardinalityPredicate{
+		expectation: e,
+		current:     p,
+		next: func() Predicate {
+			p, err := e.child.Predicate(s)
+			if err != nil {
+				// This should never occur, as the same expectation has already
+				// produced a predicate using the same scope.
+				panic(err)
+			}
+			return p
+		},
+		tracker: tracker{
+			options: s.Options,
+		},
+	}, nil
+}
+
+// cardinalityPredicate is the Predicate implementation for
+// cardinalityExpectation.
+type cardinalityPredicate struct {
+	expectation *cardinalityExpectation
+
+	// current is the predicate that is notified of new facts. It is replaced
+	// by a predicate obtained by calling next each time it is met.
+	current Predicate
+	next    func() Predicate
+
+	// history contains the facts that do not describe a produced message.
+	// Each new predicate is notified of these facts so that it has the same
+	// view of the engine's bookkeeping as the first.
+	history []fact.Fact
+
+	// count is the number of times the expectation has been met, and matches
+	// contains the envelopes of the messages that caused it to be met.
+	//
+	// The expectation is only met while the action is being performed when a
+	// new message is produced, but it may also be met once the action has
+	// completed, in which case it is counted without a matching message.
+	count   int
+	matches []*envelope.Envelope
+
+	tracker tracker
+	done    bool
+}
+
+func (p *cardinalityPredicate) Notify(f fact.Fact) {
+	env, produced := p.tracker.Notify(f)
+
+	if !produced {
+		p.history = append(p.history, f)
+	}
+
+	wasOk := p.current.Ok()
+	p.current.Notify(f)
+
+	if wasOk || !produced || !p.current.Ok() {
+		return
+	}
+
+	p.count++
+	p.matches = append(p.matches, env)
+
+	p.current.Done()
+	p.current = p.next()
+
+	for _, f := range p.history {
+		p.current.Notify(f)
+	}
+}
+
+func (p *cardinalityPredicate) Ok() bool {
+	e := p.expectation
+
+	if p.count < e.min {
+		return false
+	}
+
+	if e.max < 0 {
+		return true
+	}
+
+	// The count can only exceed the upper bound once the action has
+	// completed.
+	return p.done && p.count <= e.max
+}
+
+func (p *cardinalityPredicate) Done() {
+	// The current predicate was not ok before any facts occurred, so if it is
+	// ok now it has been met, but not by a produced message.
+	p.current.Done()
+
+	if p.current.Ok() {
+		p.count++
+	}
+
+	p.done = true
+}
+
+func (p *cardinalityPredicate) Report(ctx ReportGenerationContext) *Report {
+	e := p.expectation
+	ok := p.Ok()
+
+	// The current predicate has not been met, and hence its report describes
+	// the criteria of the expectation as a whole.
+	child := p.current.Report(ctx)
+
+	rep := &Report{
+		TreeOk: ctx.TreeOk,
+		Ok:     ok,
+		Criteria: fmt.Sprintf(
+			"%s %s %s",
+			child.Criteria,
+			e.qualifier,
+			times(e.n),
+		),
+	}
+
+	if !ok {
+		rep.Outcome = fmt.Sprintf("met %s", times(p.count))
+	}
+
+	if ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if p.count == 0 {
+		// The current predicate has been notified of every fact, so its
+		// report explains why the expectation was never met.
+		rep.Explanation = child.Explanation
+		rep.Sections = append(rep.Sections, child.Sections...)
+		return rep
+	}
+
+	if p.count < e.min {
+		rep.Explanation = fmt.Sprintf(
+			"the expectation was met %s fewer than required",
+			times(e.min-p.count),
+		)
+	} else {
+		rep.Explanation = fmt.Sprintf(
+			"the expectation was met %s more than permitted",
+			times(p.count-e.max),
+		)
+	}
+
+	suggestVerifyingHandlers(rep.Section(suggestionsSection), p.matches)
+
+	if len(p.matches) > 0 {
+		s := rep.Section(matchesSection)
+
+		for _, env := range p.matches {
+			s.AppendListItem("%s", describeEnvelope(env))
+		}
+	}
+
+	return rep
+}
+
+// times returns a description of a number of occurrences, such as "once" or
+// "3 times".
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+
+	return fmt.Sprintf("%d times", n)
+}
@@ -0,0 +1,286 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func Exactly(), AtLeast() and AtMost()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "8b4f0d2e-5a6c-4e81-9d9f-2c3a4b5d6e7f")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "9c5a1e3f-6b7d-4f92-8eaf-3d4b5c6e7f80")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						_ dogma.Command,
+					) {
+						s.RecordEvent(EventA1)
+						s.RecordEvent(EventA2)
+					},
+				})
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<other-aggregate>", "ad6b2f40-7c8e-4a03-9fb0-4e5c6d7f8091")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+							dogma.RecordsEvent[EventStub[TypeB]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "be7c3051-8d9f-4b14-a0c1-5f6d7e8092a2")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeB]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ExecuteCommand(CommandB1)
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(ExecuteCommand(CommandA1), e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"Exactly() passes when the expectation is met exactly n times",
+			Exactly(2, ToExecuteCommand(CommandB1)),
+			expectPass,
+			expectReport(
+				`✓ execute a specific 'stubs.CommandStub[TypeB]' command exactly 2 times`,
+			),
+		),
+		g.Entry(
+			"Exactly() fails when the expectation is met more than n times",
+			Exactly(1, ToExecuteCommand(CommandB1)),
+			expectFail,
+			expectReport(
+				`✗ execute a specific 'stubs.CommandStub[TypeB]' command exactly once (met 2 times)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the expectation was met once more than permitted`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+				`  | `,
+				`  | MATCHES`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+			),
+		),
+		g.Entry(
+			"Exactly() fails when the expectation is met fewer than n times",
+			Exactly(3, ToExecuteCommand(CommandB1)),
+			expectFail,
+			expectReport(
+				`✗ execute a specific 'stubs.CommandStub[TypeB]' command exactly 3 times (met 2 times)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the expectation was met once fewer than required`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+				`  | `,
+				`  | MATCHES`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+			),
+		),
+		g.Entry(
+			"Exactly() passes when n is zero and the expectation is never met",
+			Exactly(0, ToRecordEvent(EventB1)),
+			expectPass,
+			expectReport(
+				`✓ record a specific 'stubs.EventStub[TypeB]' event exactly 0 times`,
+			),
+		),
+		g.Entry(
+			"AtLeast() passes when the expectation is met n times",
+			AtLeast(2, ToRecordEventType[EventStub[TypeA]]()),
+			expectPass,
+			expectReport(
+				`✓ record any 'stubs.EventStub[TypeA]' event at least 2 times`,
+			),
+		),
+		g.Entry(
+			"AtLeast() passes when the expectation is met more than n times",
+			AtLeast(1, ToRecordEventType[EventStub[TypeA]]()),
+			expectPass,
+			expectReport(
+				`✓ record any 'stubs.EventStub[TypeA]' event at least once`,
+			),
+		),
+		g.Entry(
+			"AtLeast() fails when the expectation is met fewer than n times",
+			AtLeast(2, ToRecordEvent(EventA1)),
+			expectFail,
+			expectReport(
+				`✗ record a specific 'stubs.EventStub[TypeA]' event at least 2 times (met once)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the expectation was met once fewer than required`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`  | `,
+				`  | MATCHES`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+			),
+		),
+		g.Entry(
+			"AtLeast() fails when the expectation is never met",
+			AtLeast(1, ToRecordEvent(EventB1)),
+			expectFail,
+			expectReport(
+				`✗ record a specific 'stubs.EventStub[TypeB]' event at least once (met 0 times)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the engaged handlers recorded a matching event`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • enable integration handlers using the EnableHandlerType() option`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`  |     • verify the logic within the '<other-aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"AtMost() passes when the expectation is met n times",
+			AtMost(1, ToRecordEvent(EventA1)),
+			expectPass,
+			expectReport(
+				`✓ record a specific 'stubs.EventStub[TypeA]' event at most once`,
+			),
+		),
+		g.Entry(
+			"AtMost() passes when the expectation is never met",
+			AtMost(1, ToRecordEvent(EventB1)),
+			expectPass,
+			expectReport(
+				`✓ record a specific 'stubs.EventStub[TypeB]' event at most once`,
+			),
+		),
+		g.Entry(
+			"AtMost() fails when the expectation is met more than n times",
+			AtMost(1, ToExecuteCommand(CommandB1)),
+			expectFail,
+			expectReport(
+				`✗ execute a specific 'stubs.CommandStub[TypeB]' command at most once (met 2 times)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the expectation was met once more than permitted`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+				`  | `,
+				`  | MATCHES`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+			),
+		),
+	)
+
+	g.It("fails the test if the expectation is met before the action is performed", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandA1),
+			AtLeast(1, Not(ToRecordEvent(EventB1))),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"AtLeast(1, <expectation>): the expectation (not to record a specific 'stubs.EventStub[TypeB]' event) is met before the action is performed, so it can not be counted",
+		))
+	})
+
+	g.DescribeTable(
+		"it panics if the arguments are invalid",
+		func(fn func(), message string) {
+			gm.Expect(fn).To(gm.PanicWith(message))
+		},
+		g.Entry(
+			"Exactly() with a nil expectation",
+			func() { Exactly(1, nil) },
+			"Exactly(1, <nil>): expectation must not be nil",
+		),
+		g.Entry(
+			"Exactly() with a negative n",
+			func() { Exactly(-1, pass) },
+			"Exactly(-1, <expectation>): n must not be negative",
+		),
+		g.Entry(
+			"AtLeast() with a nil expectation",
+			func() { AtLeast(1, nil) },
+			"AtLeast(1, <nil>): expectation must not be nil",
+		),
+		g.Entry(
+			"AtLeast() with n less than 1",
+			func() { AtLeast(0, pass) },
+			"AtLeast(0, <expectation>): n must be 1 or greater",
+		),
+		g.Entry(
+			"AtMost() with a nil expectation",
+			func() { AtMost(1, nil) },
+			"AtMost(1, <nil>): expectation must not be nil",
+		),
+		g.Entry(
+			"AtMost() with a negative n",
+			func() { AtMost(-1, pass) },
+			"AtMost(-1, <expectation>): n must not be negative",
+		),
+	)
+})
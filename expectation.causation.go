@@ -0,0 +1,304 @@
+package testkit
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/inflect"
+)
+
+const (
+	// causationChainSection is the heading for the section of the test report
+	// that shows the chain of messages that caused the best match of an
+	// expectation passed to CausedBy().
+	causationChainSection = "Causation Chain"
+)
+
+// CausedBy is an expectation that passes if e passes when only the messages
+// that were produced as a consequence of handling a message equal to parent
+// are considered.
+//
+// By default only the messages produced by the handlers of the parent message
+// itself are considered. Use the [Transitively] option to also consider the
+// messages produced by the handlers of those messages, and so on.
+//
+// parent may be a message that is dispatched by the action, or a message that
+// is produced by a handler. It is compared with each message using the
+// MessageComparator from the test's predicate options.
+func CausedBy(
+	parent dogma.Message,
+	e Expectation,
+	options ...CausationOption,
+) Expectation {
+	if parent == nil {
+		panic("CausedBy(<nil>, ...): parent message must not be nil")
+	}
+
+	if e == nil {
+		panic(fmt.Sprintf("CausedBy(%s, <nil>): expectation must not be nil", message.TypeOf(parent)))
+	}
+
+	exp := &causationExpectation{
+		parent: parent,
+		child:  e,
+	}
+
+	for _, opt := range options {
+		opt.applyCausationOption(exp)
+	}
+
+	return exp
+}
+
+// CausationOption is an option that changes the behavior of [CausedBy].
+type CausationOption interface {
+	applyCausationOption(*causationExpectation)
+}
+
+type causationOptionFunc func(*causationExpectation)
+
+func (f causationOptionFunc) applyCausationOption(e *causationExpectation) {
+	f(e)
+}
+
+// Transitively returns a CausationOption that causes [CausedBy] to consider
+// messages that were caused by the parent message indirectly, in addition to
+// those caused by it directly.
+func Transitively() CausationOption {
+	return causationOptionFunc(func(e *causationExpectation) {
+		e.transitive = true
+	})
+}
+
+// causationExpectation is an Expectation that only considers messages that
+// were caused by a specific parent message.
+//
+// It is the implementation used by CausedBy().
+type causationExpectation struct {
+	parent     dogma.Message
+	child      Expectation
+	transitive bool
+}
+
+func (e *causationExpectation) Caption() string {
+	return fmt.Sprintf(
+		"%s %s",
+		e.child.Caption(),
+		e.consequence(),
+	)
+}
+
+// consequence returns the suffix that is appended to the caption and criteria
+// of the child expectation.
+func (e *causationExpectation) consequence() string {
+	mt := message.TypeOf(e.parent)
+
+	if e.transitive {
+		return fmt.Sprintf(
+			"as a consequence of a specific '%s' %s",
+			mt,
+			mt.Kind(),
+		)
+	}
+
+	return fmt.Sprintf(
+		"as a direct consequence of a specific '%s' %s",
+		mt,
+		mt.Kind(),
+	)
+}
+
+func (e *causationExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	mt := message.TypeOf(e.parent)
+
+	if _, ok := s.App.MessageTypes()[mt]; !ok {
+		return nil, inflect.Errorf(
+			mt.Kind(),
+			"a <message> of type %s can never be the cause of another message, the application does not use this message type",
+			mt,
+		)
+	}
+
+	isEqual := s.Options.MessageComparator
+	if isEqual == nil {
+		isEqual = DefaultMessageComparator
+	}
+
+	p, err := newFilteredPredicate(s, e.child)
+	if err != nil {
+		return nil, err
+	}
+
+	return &causationPredicate{
+		expectation: e,
+		isEqual:     isEqual,
+		child:       p,
+		envelopes:   map[string]*envelope.Envelope{},
+		parents:     map[string]struct{}{},
+	}, nil
+}
+
+// causationPredicate is the Predicate implementation for causationExpectation.
+type causationPredicate struct {
+	expectation *causationExpectation
+	isEqual     MessageComparator
+
+	// child is notified of the facts that relate to messages caused by the
+	// parent message.
+	child *filteredPredicate
+
+	// envelopes is the set of envelopes seen so far, keyed by message ID, and
+	// parents is the set of message IDs of those that are equal to the parent
+	// message.
+	envelopes map[string]*envelope.Envelope
+	parents   map[string]struct{}
+}
+
+func (p *causationPredicate) Notify(f fact.Fact) {
+	env, produced := producedEnvelope(f)
+
+	switch x := f.(type) {
+	case fact.DispatchCycleBegun:
+		p.observe(x.Envelope)
+	case fact.DispatchBegun:
+		p.observe(x.Envelope)
+	default:
+		if produced {
+			p.observe(env)
+		}
+	}
+
+	p.child.NotifyUnfiltered(f, func(env *envelope.Envelope) bool {
+		return p.isCaused(env, p.expectation.transitive)
+	})
+
+	if !produced || p.isCaused(env, p.expectation.transitive) {
+		p.child.NotifyChild(f)
+	}
+}
+
+// observe records env so that the causation chain of the messages it causes
+// can be determined.
+func (p *causationPredicate) observe(env *envelope.Envelope) {
+	if _, ok := p.envelopes[env.MessageID]; ok {
+		return
+	}
+
+	p.envelopes[env.MessageID] = env
+
+	if message.TypeOf(env.Message) == message.TypeOf(p.expectation.parent) &&
+		p.isEqual(env.Message, p.expectation.parent) {
+		p.parents[env.MessageID] = struct{}{}
+	}
+}
+
+// isCaused returns true if the message in env was caused by the parent
+// message, either directly or, if transitive is true, indirectly.
+func (p *causationPredicate) isCaused(env *envelope.Envelope, transitive bool) bool {
+	for {
+		if env.CausationID == env.MessageID {
+			// The message was not caused by any other message.
+			return false
+		}
+
+		if _, ok := p.parents[env.CausationID]; ok {
+			return true
+		}
+
+		if !transitive {
+			return false
+		}
+
+		cause, ok := p.envelopes[env.CausationID]
+		if !ok {
+			return false
+		}
+
+		env = cause
+	}
+}
+
+// chain returns the causation chain of env, starting with the message that
+// was not caused by any other message.
+func (p *causationPredicate) chain(env *envelope.Envelope) []*envelope.Envelope {
+	chain := []*envelope.Envelope{env}
+
+	for env.CausationID != env.MessageID {
+		cause, ok := p.envelopes[env.CausationID]
+		if !ok {
+			break
+		}
+
+		chain = append(chain, cause)
+		env = cause
+	}
+
+	slices.Reverse(chain)
+
+	return chain
+}
+
+func (p *causationPredicate) Ok() bool {
+	return p.child.Ok()
+}
+
+func (p *causationPredicate) Done() {
+	p.child.Done()
+}
+
+func (p *causationPredicate) Report(ctx ReportGenerationContext) *Report {
+	e := p.expectation
+	rep, unrelated := p.child.Report(ctx)
+
+	rep.Criteria = fmt.Sprintf(
+		"%s %s",
+		rep.Criteria,
+		e.consequence(),
+	)
+
+	if unrelated == nil {
+		return rep
+	}
+
+	k := message.KindOf(unrelated.Message)
+	pt := message.TypeOf(e.parent)
+
+	if len(p.parents) == 0 {
+		rep.Explanation = fmt.Sprintf(
+			"%s, but %s",
+			inflect.Sprint(k, "a matching <message> was <produced>"),
+			inflect.Sprintf(pt.Kind(), "no matching '%s' <message> was <produced>", pt),
+		)
+	} else {
+		rep.Explanation = fmt.Sprintf(
+			"%s, but it was not caused by the '%s' %s",
+			inflect.Sprint(k, "a matching <message> was <produced>"),
+			pt,
+			pt.Kind(),
+		)
+	}
+
+	s := rep.Section(causationChainSection)
+	for _, env := range p.chain(unrelated) {
+		s.AppendListItem("%s", describeEnvelope(env))
+	}
+
+	sug := rep.Section(suggestionsSection)
+	if unrelated.Origin != nil {
+		sug.AppendListItem(
+			"verify the logic within the '%s' %s message handler",
+			unrelated.Origin.Handler.Identity().Name,
+			unrelated.Origin.HandlerType,
+		)
+	}
+
+	if !e.transitive && p.isCaused(unrelated, true) {
+		sug.AppendListItem("use the Transitively() option if the message is an indirect consequence")
+	}
+
+	return rep
+}
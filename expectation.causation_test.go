@@ -0,0 +1,220 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func CausedBy()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "04c185a6-d2e4-4069-b516-a4b5c6d7e8f9")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "15d296b7-e3f5-417a-8627-b5c6d7e8f90a")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeB]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						switch m.(type) {
+						case CommandStub[TypeA]:
+							s.RecordEvent(EventA1)
+						case CommandStub[TypeB]:
+							s.RecordEvent(EventB1)
+						}
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "26e3a7c8-f406-428b-9738-c6d7e8f90a1b")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeB]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ExecuteCommand(CommandB1)
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(ExecuteCommand(CommandA1), e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"it passes when the message is caused directly by a dispatched message",
+			CausedBy(CommandA1, ToRecordEvent(EventA1)),
+			expectPass,
+			expectReport(
+				`✓ record a specific 'stubs.EventStub[TypeA]' event as a direct consequence of a specific 'stubs.CommandStub[TypeA]' command`,
+			),
+		),
+		g.Entry(
+			"it passes when the message is caused directly by a produced message",
+			CausedBy(EventA1, ToExecuteCommand(CommandB1)),
+			expectPass,
+			expectReport(
+				`✓ execute a specific 'stubs.CommandStub[TypeB]' command as a direct consequence of a specific 'stubs.EventStub[TypeA]' event`,
+			),
+		),
+		g.Entry(
+			"it passes when the message is caused indirectly and the Transitively() option is used",
+			CausedBy(CommandA1, ToRecordEvent(EventB1), Transitively()),
+			expectPass,
+			expectReport(
+				`✓ record a specific 'stubs.EventStub[TypeB]' event as a consequence of a specific 'stubs.CommandStub[TypeA]' command`,
+			),
+		),
+		g.Entry(
+			"it fails when the message is caused indirectly and the Transitively() option is not used",
+			CausedBy(EventA1, ToRecordEvent(EventB1)),
+			expectFail,
+			expectReport(
+				`✗ record a specific 'stubs.EventStub[TypeB]' event as a direct consequence of a specific 'stubs.EventStub[TypeA]' event`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a matching event was recorded, but it was not caused by the 'stubs.EventStub[TypeA]' event`,
+				`  | `,
+				`  | CAUSATION CHAIN`,
+				`  |     • stubs.CommandStub[TypeA] command executed via a dogma.CommandExecutor: command(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+				`  |     • stubs.EventStub[TypeB] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeB:B1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`  |     • use the Transitively() option if the message is an indirect consequence`,
+			),
+		),
+		g.Entry(
+			"it fails when the message is not caused by the parent message",
+			CausedBy(CommandB1, ToRecordEvent(EventA1), Transitively()),
+			expectFail,
+			expectReport(
+				`✗ record a specific 'stubs.EventStub[TypeA]' event as a consequence of a specific 'stubs.CommandStub[TypeB]' command`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a matching event was recorded, but it was not caused by the 'stubs.CommandStub[TypeB]' command`,
+				`  | `,
+				`  | CAUSATION CHAIN`,
+				`  |     • stubs.CommandStub[TypeA] command executed via a dogma.CommandExecutor: command(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"it fails when the parent message does not occur",
+			CausedBy(CommandA2, ToRecordEvent(EventA1)),
+			expectFail,
+			expectReport(
+				`✗ record a specific 'stubs.EventStub[TypeA]' event as a direct consequence of a specific 'stubs.CommandStub[TypeA]' command`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a matching event was recorded, but no matching 'stubs.CommandStub[TypeA]' command was executed`,
+				`  | `,
+				`  | CAUSATION CHAIN`,
+				`  |     • stubs.CommandStub[TypeA] command executed via a dogma.CommandExecutor: command(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"it fails when the message is not produced at all",
+			CausedBy(CommandA1, ToRecordEvent(EventA2)),
+			expectFail,
+			expectReport(
+				`✗ record a specific 'stubs.EventStub[TypeA]' event as a direct consequence of a specific 'stubs.CommandStub[TypeA]' command`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a similar event was recorded by the '<aggregate>' aggregate message handler`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the content of the message`,
+				`  | `,
+				`  | MESSAGE DIFF`,
+				`  |     stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]{`,
+				`  |         Content:         "A[-2-]{+1+}"`,
+				`  |         ValidationError: ""`,
+				`  |     }`,
+			),
+		),
+	)
+
+	g.It("fails the test if the parent message type is not used by the application", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandA1),
+			CausedBy(CommandC1, ToRecordEvent(EventA1)),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"a command of type stubs.CommandStub[TypeC] can never be the cause of another message, the application does not use this message type",
+		))
+	})
+
+	g.It("panics if the parent message is nil", func() {
+		gm.Expect(func() {
+			CausedBy(nil, pass)
+		}).To(gm.PanicWith("CausedBy(<nil>, ...): parent message must not be nil"))
+	})
+
+	g.It("panics if the expectation is nil", func() {
+		gm.Expect(func() {
+			CausedBy(CommandA1, nil)
+		}).To(gm.PanicWith("CausedBy(stubs.CommandStub[TypeA], <nil>): expectation must not be nil"))
+	})
+})
@@ -0,0 +1,221 @@
+package testkit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/location"
+)
+
+const (
+	// handlerErrorsSection is the heading for the section of the test report
+	// that lists the errors returned by message handlers.
+	handlerErrorsSection = "Handler Errors"
+)
+
+// ToFail returns an expectation that passes if any message handler returns an
+// error while performing the action.
+//
+// Ordinarily a handler error causes the test to fail immediately. When this
+// expectation, or any of the other ToFail*() expectations, is used the errors
+// are instead reported on by the expectation.
+func ToFail() Expectation {
+	return &failureExpectation{
+		caption:  "to fail",
+		criteria: "cause a message handler to return an error",
+	}
+}
+
+// ToFailWith returns an expectation that passes if any message handler returns
+// an error that matches target, as per errors.Is().
+func ToFailWith(target error) Expectation {
+	if target == nil {
+		panic("ToFailWith(<nil>): target error must not be nil")
+	}
+
+	return &failureExpectation{
+		caption:  fmt.Sprintf("to fail with %q", target),
+		criteria: fmt.Sprintf("cause a message handler to return an error that matches %q", target),
+		match: func(err error) error {
+			if errors.Is(err, target) {
+				return nil
+			}
+
+			return fmt.Errorf("does not match %q", target)
+		},
+	}
+}
+
+// ToFailMatching returns an expectation that passes if any message handler
+// returns an error that satisfies the given predicate function.
+//
+// Always prefer using ToFailWith() instead, if possible, as it provides more
+// meaningful information in the result of a failure.
+//
+// pred must return a non-nil error if the handler error does not meet the
+// expectation. The returned error is included in the test report.
+func ToFailMatching(pred func(error) error) Expectation {
+	if pred == nil {
+		panic("ToFailMatching(<nil>): function must not be nil")
+	}
+
+	return &failureExpectation{
+		caption: fmt.Sprintf(
+			"to fail with an error that matches the predicate near %s",
+			location.OfFunc(pred),
+		),
+		criteria: fmt.Sprintf(
+			"cause a message handler to return an error that matches the predicate near %s",
+			location.OfFunc(pred),
+		),
+		match:     pred,
+		matchFunc: true,
+	}
+}
+
+// failureExpectation is an Expectation that checks that a message handler
+// returns an error.
+//
+// It is the implementation used by ToFail(), ToFailWith() and
+// ToFailMatching().
+type failureExpectation struct {
+	caption   string
+	criteria  string
+	match     func(error) error // nil matches any error
+	matchFunc bool              // true if match is a user-supplied predicate
+}
+
+func (e *failureExpectation) Caption() string {
+	return e.caption
+}
+
+func (e *failureExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	s.expectHandlerErrors()
+
+	return &failurePredicate{
+		expectation: e,
+	}, nil
+}
+
+// handlerError is an error that was returned by a message handler.
+type handlerError struct {
+	Handler  configkit.RichHandler
+	Envelope *envelope.Envelope
+	Error    error
+
+	// Mismatch is the reason that the error does not satisfy the expectation,
+	// if any.
+	Mismatch error
+}
+
+// failurePredicate is the Predicate implementation for failureExpectation.
+type failurePredicate struct {
+	expectation *failureExpectation
+	ok          bool
+	engaged     int
+	errors      []handlerError
+}
+
+func (p *failurePredicate) Notify(f fact.Fact) {
+	switch x := f.(type) {
+	case fact.HandlingBegun:
+		p.engaged++
+	case fact.HandlingCompleted:
+		if x.Error == nil {
+			return
+		}
+
+		herr := handlerError{
+			Handler:  x.Handler,
+			Envelope: x.Envelope,
+			Error:    x.Error,
+		}
+
+		if p.expectation.match != nil {
+			herr.Mismatch = p.expectation.match(x.Error)
+		}
+
+		if herr.Mismatch == nil {
+			p.ok = true
+		}
+
+		p.errors = append(p.errors, herr)
+	}
+}
+
+func (p *failurePredicate) Ok() bool {
+	return p.ok
+}
+
+func (p *failurePredicate) Done() {
+}
+
+func (p *failurePredicate) Report(ctx ReportGenerationContext) *Report {
+	rep := &Report{
+		TreeOk:   ctx.TreeOk,
+		Ok:       p.ok,
+		Criteria: p.expectation.criteria,
+	}
+
+	if p.ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if len(p.errors) != 0 {
+		buildHandlerErrorsSection(rep, p.errors)
+	}
+
+	if p.engaged == 0 {
+		rep.Explanation = "no message handlers were engaged"
+		s := rep.Section(suggestionsSection)
+		s.AppendListItem("check the application's routing configuration")
+		s.AppendListItem("verify that the relevant handlers are enabled")
+		return rep
+	}
+
+	if len(p.errors) == 0 {
+		rep.Explanation = "none of the engaged message handlers returned an error"
+		rep.Section(suggestionsSection).AppendListItem("verify the logic within the engaged message handlers")
+		return rep
+	}
+
+	if p.expectation.matchFunc {
+		rep.Explanation = "none of the errors matched the predicate"
+
+		s := rep.Section(failedMatchesSection)
+		for _, e := range p.errors {
+			s.AppendListItem("%s", e.Mismatch)
+		}
+
+		rep.Section(suggestionsSection).AppendListItem("verify the logic within the predicate function")
+		return rep
+	}
+
+	rep.Explanation = "none of the errors matched the target error"
+	rep.Section(suggestionsSection).AppendListItem("verify that the handler returns or wraps the expected error")
+
+	return rep
+}
+
+// buildHandlerErrorsSection adds a section to the report that lists each of
+// the given handler errors, along with the handler that returned it and the
+// message that was being handled.
+func buildHandlerErrorsSection(rep *Report, errors []handlerError) {
+	s := rep.Section(handlerErrorsSection)
+
+	for _, e := range errors {
+		s.AppendListItem(
+			"%s (returned by the '%s' %s message handler while handling a %s %s: %s)",
+			e.Error,
+			e.Handler.Identity().Name,
+			e.Handler.HandlerType(),
+			message.TypeOf(e.Envelope.Message),
+			message.KindOf(e.Envelope.Message),
+			e.Envelope.Message.MessageDescription(),
+		)
+	}
+}
@@ -0,0 +1,246 @@
+package testkit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("handler failure expectations", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+		errCause error
+	)
+
+	type (
+		CommandThatFails    = CommandStub[TypeF]
+		CommandThatSucceeds = CommandStub[TypeS]
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		errCause = errors.New("<cause>")
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "8b0f7a52-6a8c-4f3e-9a0c-2e5d4b3a1f90")
+
+				c.RegisterIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("<integration>", "2c4a6e8f-1b3d-4f5a-8c7e-9d0b1a2c3e4f")
+						c.Routes(
+							dogma.HandlesCommand[CommandThatFails](),
+							dogma.HandlesCommand[CommandThatSucceeds](),
+						)
+					},
+					HandleCommandFunc: func(
+						_ context.Context,
+						_ dogma.IntegrationCommandScope,
+						m dogma.Command,
+					) error {
+						if _, ok := m.(CommandThatFails); ok {
+							return fmt.Errorf("<wrapped>: %w", errCause)
+						}
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			a Action,
+			e func() Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.EnableHandlers("<integration>")
+			test.Expect(a, e())
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"any error returned as expected",
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			func() Expectation { return ToFail() },
+			expectPass,
+			expectReport(
+				`✓ cause a message handler to return an error`,
+			),
+		),
+		g.Entry(
+			"no error returned",
+			ExecuteCommand(CommandThatSucceeds{Content: "<content>"}),
+			func() Expectation { return ToFail() },
+			expectFail,
+			expectReport(
+				`✗ cause a message handler to return an error`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the engaged message handlers returned an error`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the engaged message handlers`,
+			),
+		),
+		g.Entry(
+			"matching error returned as expected",
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			func() Expectation { return ToFailWith(errCause) },
+			expectPass,
+			expectReport(
+				`✓ cause a message handler to return an error that matches "<cause>"`,
+			),
+		),
+		g.Entry(
+			"non-matching error returned",
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			func() Expectation { return ToFailWith(errors.New("<other>")) },
+			expectFail,
+			expectReport(
+				`✗ cause a message handler to return an error that matches "<other>"`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the errors matched the target error`,
+				`  | `,
+				`  | HANDLER ERRORS`,
+				`  |     • <wrapped>: <cause> (returned by the '<integration>' integration message handler while handling a stubs.CommandStub[TypeF] command: command(stubs.TypeF:<content>, valid))`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the handler returns or wraps the expected error`,
+			),
+		),
+		g.Entry(
+			"error matching the predicate returned as expected",
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			func() Expectation {
+				return ToFailMatching(func(err error) error {
+					return nil
+				})
+			},
+			expectPass,
+			expectReport(
+				`✓ cause a message handler to return an error that matches the predicate near expectation.failure_test.go:133`,
+			),
+		),
+		g.Entry(
+			"error not matching the predicate",
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			func() Expectation {
+				return ToFailMatching(func(err error) error {
+					return errors.New("<mismatch>")
+				})
+			},
+			expectFail,
+			expectReport(
+				`✗ cause a message handler to return an error that matches the predicate near expectation.failure_test.go:145`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the errors matched the predicate`,
+				`  | `,
+				`  | HANDLER ERRORS`,
+				`  |     • <wrapped>: <cause> (returned by the '<integration>' integration message handler while handling a stubs.CommandStub[TypeF] command: command(stubs.TypeF:<content>, valid))`,
+				`  | `,
+				`  | FAILED MATCHES`,
+				`  |     • <mismatch>`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the predicate function`,
+			),
+		),
+		g.Entry(
+			"error returned unexpectedly",
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			func() Expectation { return Not(ToFail()) },
+			expectFail,
+			expectReport(
+				`✗ do not cause a message handler to return an error`,
+			),
+		),
+	)
+
+	g.It("fails the test if a handler returns an error and no failure is expected", func() {
+		test := Begin(testingT, app)
+		test.EnableHandlers("<integration>")
+		test.Expect(
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			pass,
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"<integration> integration: <wrapped>: <cause>",
+		))
+	})
+
+	g.It("fails the test if some other error occurs alongside an expected handler error", func() {
+		app := &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "5f3e1d2c-7b6a-4e9d-8c1f-0a2b3c4d5e6f")
+
+				c.RegisterIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("<integration>", "9e8d7c6b-5a4f-4e3d-b2c1-a0f9e8d7c6b5")
+						c.Routes(
+							dogma.HandlesCommand[CommandThatFails](),
+							dogma.RecordsEvent[EventStub[TypeF]](),
+						)
+					},
+					HandleCommandFunc: func(
+						_ context.Context,
+						s dogma.IntegrationCommandScope,
+						_ dogma.Command,
+					) error {
+						s.RecordEvent(EventStub[TypeF]{Content: "<event>"})
+						return errCause
+					},
+				})
+			},
+		}
+
+		test := Begin(
+			testingT,
+			app,
+			WithUnsafeEngineOptions(
+				engine.WithDispatchLimit(1),
+			),
+		)
+		test.EnableHandlers("<integration>")
+		test.Expect(
+			ExecuteCommand(CommandThatFails{Content: "<content>"}),
+			ToFailWith(errCause),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"dispatch aborted after 1 messages, the dispatch limit of 1 messages was exceeded",
+		))
+	})
+
+	g.It("panics if the target error is nil", func() {
+		gm.Expect(func() {
+			ToFailWith(nil)
+		}).To(gm.PanicWith(`ToFailWith(<nil>): target error must not be nil`))
+	})
+
+	g.It("panics if the predicate function is nil", func() {
+		gm.Expect(func() {
+			ToFailMatching(nil)
+		}).To(gm.PanicWith(`ToFailMatching(<nil>): function must not be nil`))
+	})
+})
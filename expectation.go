@@ -1,7 +1,10 @@
 package testkit
 
 import (
+	"context"
+
 	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/testkit/engine"
 	"github.com/dogmatiq/testkit/fact"
 )
 
@@ -52,6 +55,45 @@ type PredicateScope struct {
 	// Options contains values that dictate how the predicate should behave.
 	// The options are provided by the Test and the Action being performed.
 	Options PredicateOptions
+
+	// requirements is populated by predicates that need the Test to alter
+	// the way it performs the action. It may be nil.
+	requirements *predicateRequirements
+
+	// ctx and engine are used by predicates that inspect the engine's state
+	// once the action has completed. engine may be nil.
+	ctx    context.Context
+	engine *engine.Engine
+}
+
+// predicateRequirements describes changes to the way a Test performs an action
+// that are required by the predicates that observe it.
+type predicateRequirements struct {
+	// ExpectHandlerErrors is true if the predicates report on errors returned
+	// by message handlers, in which case such errors do not cause the test to
+	// fail outright.
+	ExpectHandlerErrors bool
+
+	// ExpectUnexpectedBehavior is true if the predicates report on handlers
+	// that behave in a way the engine does not expect, in which case the
+	// engine recovers from such behavior instead of panicking.
+	ExpectUnexpectedBehavior bool
+}
+
+// expectHandlerErrors indicates to the Test that the predicate reports on
+// errors returned by message handlers.
+func (s PredicateScope) expectHandlerErrors() {
+	if s.requirements != nil {
+		s.requirements.ExpectHandlerErrors = true
+	}
+}
+
+// expectUnexpectedBehavior indicates to the Test that the predicate reports on
+// handlers that behave in a way the engine does not expect.
+func (s PredicateScope) expectUnexpectedBehavior() {
+	if s.requirements != nil {
+		s.requirements.ExpectUnexpectedBehavior = true
+	}
 }
 
 // PredicateOptions contains values that dictate how a predicate should behave.
@@ -0,0 +1,403 @@
+package testkit
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/inflect"
+)
+
+const (
+	// sequenceSection is the heading for the section of the test report that
+	// describes how far through the sequence of expectations passed to
+	// InOrder() the test progressed.
+	sequenceSection = "Sequence"
+)
+
+// InOrder is an expectation that passes only if all of its children pass, in
+// the order given.
+//
+// The children are evaluated against the facts that occur while performing
+// the action in sequence. Each child is only offered the messages that are
+// produced after the previous child is met, so the same message can not meet
+// more than one child. A child is considered to be met as soon as its
+// predicate reports that it is ok, at which point it is not notified of any
+// further facts. A child that is ok before the previous child is met has been
+// met out of order, and so the sequence is broken.
+//
+// Children that are ok before any facts occur, such as Not(...) or
+// ToProduceNothing(), can not be met at a specific point during the action. As
+// such, they may only be used as the last child, in which case they are
+// evaluated against the facts that occur after the previous child is met, and
+// are met once the action completes.
+func InOrder(children ...Expectation) Expectation {
+	n := len(children)
+
+	if n == 0 {
+		panic("InOrder(): at least one child expectation must be provided")
+	}
+
+	if n == 1 {
+		return children[0]
+	}
+
+	return &inOrderExpectation{
+		children: children,
+	}
+}
+
+// inOrderExpectation is an Expectation that checks that its children are met
+// in a specific order.
+//
+// It is the implementation used by InOrder().
+type inOrderExpectation struct {
+	children []Expectation
+}
+
+func (e *inOrderExpectation) Caption() string {
+	return fmt.Sprintf("to meet %d expectations in order", len(e.children))
+}
+
+func (e *inOrderExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	var (
+		children   []Predicate
+		unfiltered []Predicate
+		last       bool
+	)
+
+	for i, c := range e.children {
+		if i > 0 {
+			// The message passed to the engine by the action is dispatched
+			// before any other message, so only the first child may be met
+			// by it.
+			s.Options.MatchDispatchCycleStartedFacts = false
+		}
+
+		p, err := c.Predicate(s)
+		if err != nil {
+			return nil, err
+		}
+
+		// The unfiltered predicate is used to explain the failure when the
+		// expected message is produced before the previous child is met.
+		var u Predicate
+		if i > 0 {
+			u, err = c.Predicate(s)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if p.Ok() {
+			if i != len(e.children)-1 {
+				return nil, fmt.Errorf(
+					"InOrder(): expectation %d (%s) is met before the action is performed, it may only be used as the last expectation",
+					i+1,
+					c.Caption(),
+				)
+			}
+
+			last = true
+		}
+
+		children = append(children, p)
+		unfiltered = append(unfiltered, u)
+	}
+
+	return &inOrderPredicate{
+		children:       children,
+		unfiltered:     unfiltered,
+		earlier:        make([]*envelope.Envelope, len(children)),
+		lastIsDeferred: last,
+	}, nil
+}
+
+// inOrderPredicate is the Predicate implementation for inOrderExpectation.
+type inOrderPredicate struct {
+	children []Predicate
+
+	// unfiltered contains a predicate for each child other than the first
+	// that is notified of every fact that occurs before that child becomes
+	// the current child.
+	unfiltered []Predicate
+
+	// earlier contains the envelope of the message that caused each of the
+	// unfiltered predicates to be met, if any.
+	earlier []*envelope.Envelope
+
+	// lastIsDeferred is true if the last child is ok before any facts occur,
+	// in which case it can only be met once the action completes.
+	lastIsDeferred bool
+
+	// met contains the position at which each child that has been met was
+	// met. Its length is the index of the child that is currently being
+	// evaluated.
+	met []sequencePosition
+
+	// early is true if the current child was already ok when the previous
+	// child was met, meaning that it was met out of order.
+	early bool
+
+	// dispatched is the number of messages dispatched so far, and lastType is
+	// the type of the most recent one.
+	dispatched int
+	lastType   message.Type
+
+	done bool
+}
+
+// sequencePosition describes the point during an action at which a child of
+// InOrder() was met.
+type sequencePosition struct {
+	// Message is the (1-based) position of the message that was being
+	// dispatched when the child was met. It is zero if the child was met
+	// before any message was dispatched.
+	Message int
+
+	// MessageType is the type of that message.
+	MessageType message.Type
+
+	// AfterAction is true if the child was met only once the action had
+	// completed.
+	AfterAction bool
+
+	// Envelope is the envelope of the produced message that caused the child
+	// to be met, if any.
+	Envelope *envelope.Envelope
+}
+
+func (p *inOrderPredicate) Notify(f fact.Fact) {
+	if x, ok := f.(fact.DispatchBegun); ok {
+		p.dispatched++
+		p.lastType = message.TypeOf(x.Envelope.Message)
+	}
+
+	n := len(p.met)
+	if n == len(p.children) {
+		return
+	}
+
+	env, produced := producedEnvelope(f)
+
+	for i := n + 1; i < len(p.children); i++ {
+		u := p.unfiltered[i]
+
+		if u.Ok() {
+			continue
+		}
+
+		u.Notify(f)
+
+		if produced && u.Ok() {
+			p.earlier[i] = env
+		}
+	}
+
+	current := p.children[n]
+	wasOk := current.Ok()
+	current.Notify(f)
+
+	// Produced messages are only offered to the current child so that the
+	// same message can not meet more than one child. The later children are
+	// notified of all other facts so that they can keep track of which
+	// handlers were engaged, and so on.
+	if !produced {
+		for _, c := range p.children[n+1:] {
+			c.Notify(f)
+		}
+	}
+
+	if !wasOk && !p.early && current.Ok() && !p.isDeferred(n) {
+		p.meet(n, env)
+	}
+}
+
+// meet records the child at index i, which must be the current child, as
+// having been met at the current position.
+//
+// env is the envelope of the produced message that caused the child to be
+// met, if any.
+func (p *inOrderPredicate) meet(i int, env *envelope.Envelope) {
+	p.met = append(
+		p.met,
+		sequencePosition{
+			Message:     p.dispatched,
+			MessageType: p.lastType,
+			AfterAction: p.done,
+			Envelope:    env,
+		},
+	)
+
+	next := i + 1
+	p.early = next < len(p.children) &&
+		!p.isDeferred(next) &&
+		p.children[next].Ok()
+}
+
+// isDeferred returns true if the child at index i can only be met once the
+// action completes.
+func (p *inOrderPredicate) isDeferred(i int) bool {
+	return p.lastIsDeferred && i == len(p.children)-1
+}
+
+func (p *inOrderPredicate) Ok() bool {
+	return len(p.met) == len(p.children)
+}
+
+func (p *inOrderPredicate) Done() {
+	// Children that become ok only once the action completes are met at that
+	// point, but children that were ok before then were met out of order.
+	wasOk := make([]bool, len(p.children))
+
+	for i, c := range p.children {
+		wasOk[i] = c.Ok()
+		c.Done()
+	}
+
+	p.done = true
+
+	for i := len(p.met); i < len(p.children); i++ {
+		c := p.children[i]
+
+		if !c.Ok() || p.early {
+			return
+		}
+
+		if i > len(p.met) && wasOk[i] && !p.isDeferred(i) {
+			return
+		}
+
+		p.meet(i, nil)
+	}
+}
+
+func (p *inOrderPredicate) Report(ctx ReportGenerationContext) *Report {
+	ok := p.Ok()
+
+	rep := &Report{
+		TreeOk:   ctx.TreeOk,
+		Ok:       ok,
+		Criteria: "in order",
+	}
+
+	if !ok {
+		rep.Outcome = fmt.Sprintf(
+			"the sequence broke down at expectation %d of %d",
+			len(p.met)+1,
+			len(p.children),
+		)
+	}
+
+	for i, c := range p.children {
+		rep.Append(p.childReport(ctx, i, c))
+	}
+
+	if ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	s := rep.Section(sequenceSection)
+
+	for i := range p.children {
+		n := i + 1
+
+		if i < len(p.met) {
+			pos := p.met[i]
+
+			switch {
+			case pos.AfterAction:
+				s.AppendListItem("expectation %d was met once the action completed", n)
+			case pos.Message == 0:
+				s.AppendListItem("expectation %d was met before any messages were dispatched", n)
+			default:
+				s.AppendListItem(
+					"expectation %d was met while dispatching message %d (%s %s)",
+					n,
+					pos.Message,
+					pos.MessageType,
+					pos.MessageType.Kind(),
+				)
+			}
+
+			continue
+		}
+
+		if i > len(p.met) {
+			s.AppendListItem("expectation %d was not evaluated", n)
+			continue
+		}
+
+		if p.early {
+			s.AppendListItem("expectation %d was met before expectation %d", n, i)
+			continue
+		}
+
+		// The child was notified of the facts that occurred after the
+		// previous child was met, which may have been partway through the
+		// dispatching of a message.
+		from := 1
+		if i > 0 {
+			prev := p.met[i-1]
+			from = max(prev.Message, 1)
+
+			if prev.AfterAction {
+				from = p.dispatched + 1
+			}
+		}
+
+		if from > p.dispatched {
+			s.AppendListItem("expectation %d was not met, no further messages were dispatched", n)
+		} else if from == p.dispatched {
+			s.AppendListItem("expectation %d was not met by message %d", n, from)
+		} else {
+			s.AppendListItem(
+				"expectation %d was not met by messages %d to %d",
+				n,
+				from,
+				p.dispatched,
+			)
+		}
+	}
+
+	return rep
+}
+
+// childReport returns the report for the child c at index i.
+func (p *inOrderPredicate) childReport(
+	ctx ReportGenerationContext,
+	i int,
+	c Predicate,
+) *Report {
+	rep := c.Report(ctx)
+
+	env := p.earlier[i]
+	if rep.Ok || ctx.TreeOk || ctx.IsInverted || env == nil || i != len(p.met) {
+		return rep
+	}
+
+	// The child would have been met if the matching message had been produced
+	// after the previous child was met, so the child's own explanation and
+	// suggestions are not relevant.
+	k := message.KindOf(env.Message)
+	rep.Sections = nil
+
+	if p.met[i-1].Envelope == env {
+		rep.Explanation = inflect.Sprintf(
+			k,
+			"a matching <message> was <produced>, but it was used to meet expectation %d",
+			i,
+		)
+	} else {
+		rep.Explanation = inflect.Sprintf(
+			k,
+			"a matching <message> was <produced> before expectation %d was met",
+			i,
+		)
+	}
+
+	suggestVerifyingHandlers(rep.Section(suggestionsSection), []*envelope.Envelope{env})
+
+	return rep
+}
@@ -0,0 +1,218 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func InOrder()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "4e1c7a9b-2d3f-4b5e-8a6c-9f0d1e2a3b4c")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "5f2d8b0c-3e4a-4c6f-9b7d-0a1e2f3b4c5d")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeB]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						switch m.(type) {
+						case CommandStub[TypeA]:
+							s.RecordEvent(EventA1)
+						case CommandStub[TypeB]:
+							s.RecordEvent(EventB1)
+						}
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "6a3e9c1d-4f5b-4d70-8c8e-1b2f3a4c5d6e")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeB]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ExecuteCommand(CommandB1)
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(ExecuteCommand(CommandA1), e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"it flattens report output when there is a single child",
+			InOrder(ToRecordEvent(EventA1)),
+			expectPass,
+			expectReport(
+				`✓ record a specific 'stubs.EventStub[TypeA]' event`,
+			),
+		),
+		g.Entry(
+			"it passes when the child expectations are met in order",
+			InOrder(
+				ToRecordEvent(EventA1),
+				ToExecuteCommand(CommandB1),
+				ToRecordEvent(EventB1),
+			),
+			expectPass,
+			expectReport(
+				`✓ in order`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✓ execute a specific 'stubs.CommandStub[TypeB]' command`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+			),
+		),
+		g.Entry(
+			"it fails when the child expectations are met out of order",
+			InOrder(
+				ToRecordEvent(EventA1),
+				ToRecordEvent(EventB1),
+				ToExecuteCommand(CommandB1),
+			),
+			expectFail,
+			expectReport(
+				`✗ in order (the sequence broke down at expectation 3 of 3)`,
+				``,
+				`  | SEQUENCE`,
+				`  |     • expectation 1 was met while dispatching message 1 (stubs.CommandStub[TypeA] command)`,
+				`  |     • expectation 2 was met while dispatching message 3 (stubs.CommandStub[TypeB] command)`,
+				`  |     • expectation 3 was not met by messages 3 to 4`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+				`    ✗ execute a specific 'stubs.CommandStub[TypeB]' command`,
+				`    `,
+				`      | EXPLANATION`,
+				`      |     a matching command was executed before expectation 2 was met`,
+				`      | `,
+				`      | SUGGESTIONS`,
+				`      |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"it fails when the same message is expected more than once",
+			InOrder(
+				ToRecordEvent(EventA1),
+				ToRecordEvent(EventA1),
+			),
+			expectFail,
+			expectReport(
+				`✗ in order (the sequence broke down at expectation 2 of 2)`,
+				``,
+				`  | SEQUENCE`,
+				`  |     • expectation 1 was met while dispatching message 1 (stubs.CommandStub[TypeA] command)`,
+				`  |     • expectation 2 was not met by messages 1 to 4`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✗ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    `,
+				`      | EXPLANATION`,
+				`      |     a matching event was recorded, but it was used to meet expectation 1`,
+				`      | `,
+				`      | SUGGESTIONS`,
+				`      |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"it passes when a negated last child is not met after the previous child",
+			InOrder(
+				ToRecordEvent(EventA1),
+				Not(ToRecordEvent(EventA1)),
+			),
+			expectPass,
+			expectReport(
+				`✓ in order`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✓ do not record a specific 'stubs.EventStub[TypeA]' event`,
+			),
+		),
+		g.Entry(
+			"it fails when a negated last child is met after the previous child",
+			InOrder(
+				ToRecordEvent(EventA1),
+				Not(ToRecordEvent(EventB1)),
+			),
+			expectFail,
+			expectReport(
+				`✗ in order (the sequence broke down at expectation 2 of 2)`,
+				``,
+				`  | SEQUENCE`,
+				`  |     • expectation 1 was met while dispatching message 1 (stubs.CommandStub[TypeA] command)`,
+				`  |     • expectation 2 was not met by messages 1 to 4`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✗ do not record a specific 'stubs.EventStub[TypeB]' event`,
+			),
+		),
+	)
+
+	g.It("fails the test if a child that is met before the action is performed is not the last child", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandA1),
+			InOrder(
+				Not(ToRecordEvent(EventB1)),
+				ToRecordEvent(EventA1),
+			),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"InOrder(): expectation 1 (not to record a specific 'stubs.EventStub[TypeB]' event) is met before the action is performed, it may only be used as the last expectation",
+		))
+	})
+
+	g.It("panics if no children are provided", func() {
+		gm.Expect(func() {
+			InOrder()
+		}).To(gm.PanicWith("InOrder(): at least one child expectation must be provided"))
+	})
+})
@@ -0,0 +1,244 @@
+package testkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+const (
+	// instancesSection is the heading for the section of the test report
+	// that lists the aggregate or process instances that were engaged while
+	// performing an action.
+	instancesSection = "Instances"
+)
+
+// guardAgainstExpectationOnUnknownHandler returns an error if the application
+// within scope s does not have a handler of type ht named n.
+func guardAgainstExpectationOnUnknownHandler(
+	s PredicateScope,
+	n string,
+	ht configkit.HandlerType,
+) error {
+	// TODO: These checks should result in information being added to the
+	// report, not just returning an error.
+	//
+	// See https://github.com/dogmatiq/testkit/issues/162
+	_, err := lookupHandlerOfType(s.App, n, ht)
+	return err
+}
+
+// lookupHandler returns the handler named n within app. It returns an error if
+// there is no such handler.
+func lookupHandler(
+	app configkit.RichApplication,
+	n string,
+) (configkit.RichHandler, error) {
+	h, ok := app.RichHandlers().ByName(n)
+	if !ok {
+		return nil, fmt.Errorf(
+			"the '%s' application does not have a handler named '%s'",
+			app.Identity().Name,
+			n,
+		)
+	}
+
+	return h, nil
+}
+
+// lookupHandlerOfType returns the handler named n within app. It returns an
+// error if there is no such handler, or if it is not of type ht.
+func lookupHandlerOfType(
+	app configkit.RichApplication,
+	n string,
+	ht configkit.HandlerType,
+) (configkit.RichHandler, error) {
+	h, err := lookupHandler(app, n)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.HandlerType() != ht {
+		return nil, fmt.Errorf(
+			"'%s' is %s %s message handler, not %s %s message handler",
+			n,
+			article(h.HandlerType()),
+			h.HandlerType(),
+			article(ht),
+			ht,
+		)
+	}
+
+	return h, nil
+}
+
+// article returns the indefinite article to use before the name of the
+// handler type ht.
+func article(ht configkit.HandlerType) string {
+	if ht == configkit.AggregateHandlerType || ht == configkit.IntegrationHandlerType {
+		return "an"
+	}
+
+	return "a"
+}
+
+// instanceTracker is a fact.Observer used by expectations that need to keep
+// track of the lifecycle of the instances of a specific aggregate or process
+// message handler.
+type instanceTracker struct {
+	// handler is the name of the handler that manages the instances.
+	handler string
+
+	// engaged is true if the handler was engaged to handle at least one
+	// message.
+	engaged bool
+
+	// skipped is true if the handler was skipped while handling at least one
+	// message.
+	skipped bool
+
+	// order is the order in which instances were first mentioned by a fact.
+	order []string
+
+	// history is the list of lifecycle changes that occurred to each instance.
+	history map[string][]string
+}
+
+// Notify updates the tracker's state in response to a new fact.
+func (t *instanceTracker) Notify(f fact.Fact) {
+	switch x := f.(type) {
+	case fact.HandlingBegun:
+		if x.Handler.Identity().Name == t.handler {
+			t.engaged = true
+		}
+	case fact.HandlingSkipped:
+		if x.Handler.Identity().Name == t.handler {
+			t.skipped = true
+		}
+	}
+}
+
+// record adds an entry to the history of the instance with the given ID.
+func (t *instanceTracker) record(id, change string) {
+	if t.history == nil {
+		t.history = map[string][]string{}
+	}
+
+	if _, ok := t.history[id]; !ok {
+		t.order = append(t.order, id)
+	}
+
+	t.history[id] = append(t.history[id], change)
+}
+
+// has returns true if the instance with the given ID underwent the given
+// lifecycle change.
+func (t *instanceTracker) has(id, change string) bool {
+	for _, c := range t.history[id] {
+		if c == change {
+			return true
+		}
+	}
+
+	return false
+}
+
+// underwent returns true if the instance with the given ID underwent the given
+// lifecycle change without that change being immediately followed by the
+// revert change.
+func (t *instanceTracker) underwent(id, change, revert string) bool {
+	history := t.history[id]
+
+	for i, c := range history {
+		if c != change {
+			continue
+		}
+
+		if i+1 < len(history) && history[i+1] == revert {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// underwentAny returns true if any instance underwent the given lifecycle
+// change without that change being immediately followed by the revert change.
+func (t *instanceTracker) underwentAny(change, revert string) bool {
+	for _, id := range t.order {
+		if t.underwent(id, change, revert) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// others returns the IDs of the instances other than id that underwent the
+// given lifecycle change.
+func (t *instanceTracker) others(id, change string) []string {
+	var ids []string
+
+	for _, x := range t.order {
+		if x != id && t.has(x, change) {
+			ids = append(ids, x)
+		}
+	}
+
+	return ids
+}
+
+// buildInstancesSection adds a section to the report that lists each of the
+// instances that were engaged and how their lifecycle changed.
+func (t *instanceTracker) buildInstancesSection(rep *Report) {
+	s := rep.Section(instancesSection)
+
+	for _, id := range t.order {
+		s.AppendListItem(
+			"%s: %s",
+			id,
+			strings.Join(t.history[id], ", "),
+		)
+	}
+}
+
+// reportNotEngaged populates rep with an explanation of why the handler was
+// not engaged. It returns false if the handler was engaged.
+func (t *instanceTracker) reportNotEngaged(
+	rep *Report,
+	ht configkit.HandlerType,
+) bool {
+	if t.engaged {
+		return false
+	}
+
+	s := rep.Section(suggestionsSection)
+
+	rep.Explanation = fmt.Sprintf(
+		"the '%s' %s message handler was not engaged",
+		t.handler,
+		ht,
+	)
+
+	if t.skipped {
+		s.AppendListItem("enable the '%s' %s message handler", t.handler, ht)
+	} else {
+		s.AppendListItem("check the application's routing configuration")
+	}
+
+	return true
+}
+
+// quoteIDs returns a human-readable list of instance IDs.
+func quoteIDs(ids []string) string {
+	var quoted []string
+	for _, id := range ids {
+		quoted = append(quoted, "'"+id+"'")
+	}
+
+	return strings.Join(quoted, ", ")
+}
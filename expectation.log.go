@@ -0,0 +1,221 @@
+package testkit
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+const (
+	// handlerLogSection is the heading for the section of the test report that
+	// lists the log messages written by handlers, as inspected by ToLog() and
+	// ToLogFrom().
+	handlerLogSection = "Handler Log Messages"
+)
+
+// ToLog returns an expectation that passes if any handler writes a log
+// message that matches the regular expression pattern.
+//
+// The log message is matched after it is formatted with its arguments.
+func ToLog(pattern string) Expectation {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("ToLog(%#v): %s", pattern, err))
+	}
+
+	return &logExpectation{
+		pattern: re,
+	}
+}
+
+// ToLogFrom returns an expectation that passes if the handler named handler
+// writes a log message that matches the regular expression pattern.
+//
+// The log message is matched after it is formatted with its arguments.
+func ToLogFrom(handler, pattern string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToLogFrom(%#v, %#v): handler name must not be empty", handler, pattern))
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("ToLogFrom(%#v, %#v): %s", handler, pattern, err))
+	}
+
+	return &logExpectation{
+		handler: handler,
+		pattern: re,
+	}
+}
+
+// logExpectation is an Expectation that checks that a handler writes a
+// specific log message.
+//
+// It is the implementation used by ToLog() and ToLogFrom().
+type logExpectation struct {
+	handler string // empty if any handler is permitted
+	pattern *regexp.Regexp
+}
+
+func (e *logExpectation) Caption() string {
+	if e.handler == "" {
+		return fmt.Sprintf(
+			"to log a message that matches the regular expression (%s)",
+			e.pattern,
+		)
+	}
+
+	return fmt.Sprintf(
+		"to cause the '%s' handler to log a message that matches the regular expression (%s)",
+		e.handler,
+		e.pattern,
+	)
+}
+
+func (e *logExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	p := &logPredicate{
+		expectation: e,
+	}
+
+	if e.handler != "" {
+		h, err := lookupHandler(s.App, e.handler)
+		if err != nil {
+			return nil, err
+		}
+
+		p.handlerType = h.HandlerType()
+	}
+
+	return p, nil
+}
+
+// logPredicate is the Predicate implementation for logExpectation.
+type logPredicate struct {
+	expectation *logExpectation
+	handlerType configkit.HandlerType
+	ok          bool
+
+	// logged contains the log messages written by the relevant handlers, in
+	// the order they were written.
+	logged []loggedMessage
+}
+
+// loggedMessage is a log message written by a handler.
+type loggedMessage struct {
+	Handler configkit.RichHandler
+	Text    string
+}
+
+func (p *logPredicate) Notify(f fact.Fact) {
+	if p.ok {
+		return
+	}
+
+	m, ok := loggedMessageOf(f)
+	if !ok {
+		return
+	}
+
+	if p.expectation.handler != "" && m.Handler.Identity().Name != p.expectation.handler {
+		return
+	}
+
+	p.logged = append(p.logged, m)
+	p.ok = p.expectation.pattern.MatchString(m.Text)
+}
+
+func (p *logPredicate) Ok() bool {
+	return p.ok
+}
+
+func (p *logPredicate) Done() {
+}
+
+func (p *logPredicate) Report(ctx ReportGenerationContext) *Report {
+	e := p.expectation
+
+	rep := &Report{
+		TreeOk: ctx.TreeOk,
+		Ok:     p.ok,
+		Criteria: fmt.Sprintf(
+			"log a message that matches the regular expression (%s)",
+			e.pattern,
+		),
+	}
+
+	if e.handler != "" {
+		rep.Criteria = fmt.Sprintf(
+			"cause the '%s' %s to %s",
+			e.handler,
+			p.handlerType,
+			rep.Criteria,
+		)
+	}
+
+	if p.ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	s := rep.Section(suggestionsSection)
+
+	if len(p.logged) == 0 {
+		if e.handler == "" {
+			rep.Explanation = "no log messages were written"
+		} else {
+			rep.Explanation = fmt.Sprintf(
+				"the '%s' %s message handler did not write any log messages",
+				e.handler,
+				p.handlerType,
+			)
+			s.AppendListItem(
+				"verify the logic within the '%s' %s message handler",
+				e.handler,
+				p.handlerType,
+			)
+		}
+
+		return rep
+	}
+
+	if len(p.logged) == 1 {
+		rep.Explanation = "the only log message that was written does not match"
+	} else {
+		rep.Explanation = fmt.Sprintf(
+			"none of the %d log messages that were written match",
+			len(p.logged),
+		)
+	}
+
+	s.AppendListItem("check the regular expression")
+
+	list := rep.Section(handlerLogSection)
+	for _, m := range p.logged {
+		list.AppendListItem(
+			"'%s' %s: %s",
+			m.Handler.Identity().Name,
+			m.Handler.HandlerType(),
+			m.Text,
+		)
+	}
+
+	return rep
+}
+
+// loggedMessageOf returns the log message described by f, if f is a fact that
+// describes a handler writing a log message.
+func loggedMessageOf(f fact.Fact) (loggedMessage, bool) {
+	switch x := f.(type) {
+	case fact.MessageLoggedByAggregate:
+		return loggedMessage{x.Handler, fmt.Sprintf(x.LogFormat, x.LogArguments...)}, true
+	case fact.MessageLoggedByProcess:
+		return loggedMessage{x.Handler, fmt.Sprintf(x.LogFormat, x.LogArguments...)}, true
+	case fact.MessageLoggedByIntegration:
+		return loggedMessage{x.Handler, fmt.Sprintf(x.LogFormat, x.LogArguments...)}, true
+	case fact.MessageLoggedByProjection:
+		return loggedMessage{x.Handler, fmt.Sprintf(x.LogFormat, x.LogArguments...)}, true
+	}
+
+	return loggedMessage{}, false
+}
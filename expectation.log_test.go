@@ -0,0 +1,197 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToLog() and ToLogFrom()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "6a27ebfc-384a-46cf-9b7c-0a1b2c3d4e5f")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "7b38fc0d-495b-47d0-8c8d-1b2c3d4e5f60")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						_ dogma.Command,
+					) {
+						s.Log("opened account %s", s.InstanceID())
+						s.RecordEvent(EventA1)
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "8c490d1e-5a6c-48e1-9d9e-2c3d4e5f6071")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeB]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.Log("sent %d reminders", 3)
+						return nil
+					},
+				})
+
+				c.RegisterIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("<integration>", "9d5a1e2f-6b7d-49f2-8eaf-3d4e5f607182")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+						)
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(ExecuteCommand(CommandA1), e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"ToLog() passes when a matching message is logged",
+			ToLog(`^sent \d+ reminders$`),
+			expectPass,
+			expectReport(
+				`✓ log a message that matches the regular expression (^sent \d+ reminders$)`,
+			),
+		),
+		g.Entry(
+			"ToLog() fails when no matching message is logged",
+			ToLog(`closed account`),
+			expectFail,
+			expectReport(
+				`✗ log a message that matches the regular expression (closed account)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the 2 log messages that were written match`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the regular expression`,
+				`  | `,
+				`  | HANDLER LOG MESSAGES`,
+				`  |     • '<aggregate>' aggregate: opened account <instance>`,
+				`  |     • '<process>' process: sent 3 reminders`,
+			),
+		),
+		g.Entry(
+			"ToLogFrom() passes when a matching message is logged by the handler",
+			ToLogFrom("<aggregate>", `opened account <instance>`),
+			expectPass,
+			expectReport(
+				`✓ cause the '<aggregate>' aggregate to log a message that matches the regular expression (opened account <instance>)`,
+			),
+		),
+		g.Entry(
+			"ToLogFrom() fails when a matching message is logged by some other handler",
+			ToLogFrom("<process>", `opened account`),
+			expectFail,
+			expectReport(
+				`✗ cause the '<process>' process to log a message that matches the regular expression (opened account)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the only log message that was written does not match`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the regular expression`,
+				`  | `,
+				`  | HANDLER LOG MESSAGES`,
+				`  |     • '<process>' process: sent 3 reminders`,
+			),
+		),
+		g.Entry(
+			"ToLogFrom() fails when the handler does not log any messages",
+			ToLogFrom("<integration>", `.`),
+			expectFail,
+			expectReport(
+				`✗ cause the '<integration>' integration to log a message that matches the regular expression (.)`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the '<integration>' integration message handler did not write any log messages`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<integration>' integration message handler`,
+			),
+		),
+	)
+
+	g.It("fails the test if the handler does not exist", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandA1),
+			ToLogFrom("<unknown>", `.`),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"the '<app>' application does not have a handler named '<unknown>'",
+		))
+	})
+
+	g.DescribeTable(
+		"it panics if the arguments are invalid",
+		func(fn func(), message string) {
+			gm.Expect(fn).To(gm.PanicWith(message))
+		},
+		g.Entry(
+			"ToLog() with an invalid regular expression",
+			func() { ToLog(`(`) },
+			"ToLog(\"(\"): error parsing regexp: missing closing ): `(`",
+		),
+		g.Entry(
+			"ToLogFrom() with an empty handler name",
+			func() { ToLogFrom("", `.`) },
+			`ToLogFrom("", "."): handler name must not be empty`,
+		),
+		g.Entry(
+			"ToLogFrom() with an invalid regular expression",
+			func() { ToLogFrom("<aggregate>", `(`) },
+			"ToLogFrom(\"<aggregate>\", \"(\"): error parsing regexp: missing closing ): `(`",
+		),
+	)
+})
@@ -48,19 +48,44 @@ func ToRecordEvent(m dogma.Event) Expectation {
 	}
 }
 
+// ToScheduleTimeout returns an expectation that passes if a timeout is
+// scheduled that is equal to m.
+//
+// The options may be used to constrain the time at which the timeout is
+// scheduled.
+func ToScheduleTimeout(m dogma.Timeout, options ...ScheduleOption) Expectation {
+	if m == nil {
+		panic("ToScheduleTimeout(<nil>): message must not be nil")
+	}
+
+	mt := message.TypeOf(m)
+
+	if err := m.Validate(validation.TimeoutValidationScope()); err != nil {
+		panic(fmt.Sprintf("ToScheduleTimeout(%s): %s", mt, err))
+	}
+
+	return &messageExpectation{
+		expectedMessage: m,
+		schedule:        newScheduleConstraints(options),
+	}
+}
+
 // messageTypeExpectation is an Expectation that checks that specific message is
 // produced.
 //
-// It is the implementation used by ToExecuteCommand() and ToRecordEvent().
+// It is the implementation used by ToExecuteCommand(), ToRecordEvent() and
+// ToScheduleTimeout().
 type messageExpectation struct {
 	expectedMessage dogma.Message
+	schedule        scheduleConstraints
 }
 
 func (e *messageExpectation) Caption() string {
 	return inflect.Sprintf(
 		message.KindOf(e.expectedMessage),
-		"to <produce> a specific '%s' <message>",
+		"to <produce> a specific '%s' <message>%s",
 		message.TypeOf(e.expectedMessage),
+		e.schedule,
 	)
 }
 
@@ -74,6 +99,7 @@ func (e *messageExpectation) Predicate(s PredicateScope) (Predicate, error) {
 	return &messagePredicate{
 		messageComparator: s.Options.MessageComparator,
 		expectedMessage:   e.expectedMessage,
+		schedule:          e.schedule,
 		tracker: tracker{
 			kind:    mt.Kind(),
 			options: s.Options,
@@ -85,8 +111,10 @@ func (e *messageExpectation) Predicate(s PredicateScope) (Predicate, error) {
 type messagePredicate struct {
 	messageComparator MessageComparator
 	expectedMessage   dogma.Message
+	schedule          scheduleConstraints
 	ok                bool
 	bestMatch         *envelope.Envelope
+	bestMatchIsEqual  bool
 	tracker           tracker
 }
 
@@ -110,8 +138,16 @@ func (p *messagePredicate) Notify(f fact.Fact) {
 		isEqual = DefaultMessageComparator
 	}
 
-	p.bestMatch = env
-	p.ok = isEqual(env.Message, p.expectedMessage)
+	if isEqual(env.Message, p.expectedMessage) {
+		p.bestMatch = env
+		p.bestMatchIsEqual = true
+		p.ok = p.schedule.IsSatisfiedBy(env)
+	} else if !p.bestMatchIsEqual {
+		// Only replace the best-match if we have not already found a message
+		// that is equal to the expected message but was scheduled for the
+		// wrong time.
+		p.bestMatch = env
+	}
 }
 
 func (p *messagePredicate) Ok() bool {
@@ -129,8 +165,9 @@ func (p *messagePredicate) Report(ctx ReportGenerationContext) *Report {
 		Ok:     p.ok,
 		Criteria: inflect.Sprintf(
 			mt.Kind(),
-			"<produce> a specific '%s' <message>",
+			"<produce> a specific '%s' <message>%s",
 			mt,
+			p.schedule,
 		),
 	}
 
@@ -145,6 +182,19 @@ func (p *messagePredicate) Report(ctx ReportGenerationContext) *Report {
 
 	s := rep.Section(suggestionsSection)
 
+	if p.bestMatchIsEqual {
+		rep.Explanation = inflect.Sprintf(
+			mt.Kind(),
+			"the <message> was <produced> by the '%s' %s message handler, but it was %s",
+			p.bestMatch.Origin.Handler.Identity().Name,
+			p.bestMatch.Origin.HandlerType,
+			p.schedule.Violation(p.bestMatch),
+		)
+
+		s.AppendListItem("check the time at which the timeout is scheduled")
+		return rep
+	}
+
 	if p.bestMatch.Origin == nil {
 		rep.Explanation = inflect.Sprint(
 			mt.Kind(),
@@ -0,0 +1,277 @@
+package testkit_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToScheduleTimeout()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		EventThatIsIgnored        = EventStub[TypeX]
+		EventThatSchedulesTimeout = EventStub[TypeT]
+		EventThatExecutesCommand  = EventStub[TypeC]
+
+		CommandThatIsExecuted = CommandStub[TypeC]
+
+		TimeoutThatIsScheduled      = TimeoutStub[TypeT]
+		TimeoutThatIsNeverScheduled = TimeoutStub[TypeX]
+	)
+
+	startTime := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)
+	scheduledFor := startTime.Add(1 * time.Hour)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "9ec6a86e-3bde-4a0b-8e2c-f48be1ab9be2")
+
+				// Register a process that will schedule the timeouts about
+				// which we will make assertions using ToScheduleTimeout().
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "bf3ee3fb-b4c8-4c7c-9ad7-76a27b3e0d7c")
+						c.Routes(
+							dogma.HandlesEvent[EventThatIsIgnored](),
+
+							dogma.HandlesEvent[EventThatSchedulesTimeout](),
+							dogma.SchedulesTimeout[TimeoutThatIsScheduled](),
+							dogma.SchedulesTimeout[TimeoutThatIsNeverScheduled](),
+
+							dogma.HandlesEvent[EventThatExecutesCommand](),
+							dogma.ExecutesCommand[CommandThatIsExecuted](),
+						)
+					},
+					RouteEventToInstanceFunc: func(
+						context.Context,
+						dogma.Event,
+					) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						m dogma.Event,
+					) error {
+						switch m := m.(type) {
+						case EventThatSchedulesTimeout:
+							s.ScheduleTimeout(
+								TimeoutThatIsScheduled{
+									Content: m.Content,
+								},
+								scheduledFor,
+							)
+						case EventThatExecutesCommand:
+							s.ExecuteCommand(CommandThatIsExecuted{})
+						}
+
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+			options ...TestOption,
+		) {
+			options = append(options, StartTimeAt(startTime))
+			test := Begin(testingT, app, options...)
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"timeout scheduled as expected",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeout(TimeoutThatIsScheduled{}),
+			expectPass,
+			expectReport(
+				`✓ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout`,
+			),
+		),
+		g.Entry(
+			"timeout scheduled for the expected time",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeout(
+				TimeoutThatIsScheduled{},
+				ScheduledFor(scheduledFor),
+			),
+			expectPass,
+			expectReport(
+				`✓ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout for 2100-01-02T04:04:05Z`,
+			),
+		),
+		g.Entry(
+			"timeout scheduled within the expected window",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeout(
+				TimeoutThatIsScheduled{},
+				ScheduledAfter(startTime),
+				ScheduledBefore(scheduledFor.Add(1*time.Minute)),
+			),
+			expectPass,
+			expectReport(
+				`✓ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout after 2100-01-02T03:04:05Z and before 2100-01-02T04:05:05Z`,
+			),
+		),
+		g.Entry(
+			"timeout scheduled for the wrong time",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeout(
+				TimeoutThatIsScheduled{},
+				ScheduledWithin(startTime, startTime.Add(1*time.Minute)),
+			),
+			expectFail,
+			expectReport(
+				`✗ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout between 2100-01-02T03:04:05Z and 2100-01-02T03:05:05Z`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the timeout was scheduled by the '<process>' process message handler, but it was scheduled for 2100-01-02T04:04:05Z, expected between 2100-01-02T03:04:05Z and 2100-01-02T03:05:05Z`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the time at which the timeout is scheduled`,
+			),
+		),
+		g.Entry(
+			"no matching timeout scheduled",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeout(TimeoutThatIsNeverScheduled{}),
+			expectFail,
+			expectReport(
+				`✗ schedule a specific 'stubs.TimeoutStub[TypeX]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the engaged handlers scheduled a matching timeout`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"no messages produced at all",
+			RecordEvent(EventThatIsIgnored{}),
+			ToScheduleTimeout(TimeoutThatIsScheduled{}),
+			expectFail,
+			expectReport(
+				`✗ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     no messages were produced at all`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"no timeouts produced at all",
+			RecordEvent(EventThatExecutesCommand{}),
+			ToScheduleTimeout(TimeoutThatIsScheduled{}),
+			expectFail,
+			expectReport(
+				`✗ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     no timeouts were scheduled at all`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"no matching timeout scheduled and all relevant handler types disabled",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeout(TimeoutThatIsScheduled{}),
+			expectFail,
+			expectReport(
+				`✗ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     no relevant handler types were enabled`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • enable process handlers using the EnableHandlerType() option`,
+			),
+			WithUnsafeOperationOptions(
+				engine.EnableProcesses(false),
+			),
+		),
+		g.Entry(
+			"similar timeout scheduled with a different value",
+			RecordEvent(EventThatSchedulesTimeout{Content: "<content>"}),
+			ToScheduleTimeout(TimeoutThatIsScheduled{Content: "<different>"}),
+			expectFail,
+			expectReport(
+				`✗ schedule a specific 'stubs.TimeoutStub[TypeT]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a similar timeout was scheduled by the '<process>' process message handler`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the content of the message`,
+				`  | `,
+				`  | MESSAGE DIFF`,
+				`  |     stubs.TimeoutStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeT]{`,
+				`  |         Content:         "<[-differ-]{+cont+}ent>"`,
+				`  |         ValidationError: ""`,
+				`  |     }`,
+			),
+		),
+	)
+
+	g.It("fails the test if the message type is unrecognized", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToScheduleTimeout(TimeoutU1),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"a timeout of type stubs.TimeoutStub[TypeU] can never be scheduled, the application does not use this message type",
+		))
+	})
+
+	g.It("panics if the message is nil", func() {
+		gm.Expect(func() {
+			ToScheduleTimeout(nil)
+		}).To(gm.PanicWith("ToScheduleTimeout(<nil>): message must not be nil"))
+	})
+
+	g.It("panics if the message is invalid", func() {
+		gm.Expect(func() {
+			ToScheduleTimeout(TimeoutStub[TypeA]{
+				ValidationError: "<invalid>",
+			})
+		}).To(gm.PanicWith("ToScheduleTimeout(stubs.TimeoutStub[TypeA]): <invalid>"))
+	})
+
+	g.It("panics if the scheduling window ends before it starts", func() {
+		gm.Expect(func() {
+			ScheduledWithin(scheduledFor, startTime)
+		}).To(gm.PanicWith("ScheduledWithin(2100-01-02T04:04:05Z, 2100-01-02T03:04:05Z): end must not be before start"))
+	})
+})
@@ -67,7 +67,9 @@ func reportNoMatch(rep *Report, t *tracker) {
 		s.AppendListItem("verify the logic within the '%s' %s message handler", n, t.engagedType[n])
 	}
 
-	if t.options.MatchDispatchCycleStartedFacts {
+	// Timeouts can never be produced via a dispatcher, so there is no code to
+	// verify.
+	if t.options.MatchDispatchCycleStartedFacts && t.kind != message.TimeoutKind {
 		s.AppendListItem(inflect.Sprint(t.kind, "verify the logic within the code that uses the <dispatcher>"))
 	}
 }
@@ -189,3 +191,172 @@ func (t *tracker) messageProduced(env *envelope.Envelope) {
 		t.produced++
 	}
 }
+
+// describeEnvelope returns a human-readable description of the message in env
+// and the manner in which it was produced.
+func describeEnvelope(env *envelope.Envelope) string {
+	k := message.KindOf(env.Message)
+
+	if env.Origin == nil {
+		return inflect.Sprintf(
+			k,
+			"%s <message> <produced> via a <dispatcher>: %s",
+			message.TypeOf(env.Message),
+			env.Message.MessageDescription(),
+		)
+	}
+
+	return inflect.Sprintf(
+		k,
+		"%s <message> <produced> by the '%s' %s message handler: %s",
+		message.TypeOf(env.Message),
+		env.Origin.Handler.Identity().Name,
+		env.Origin.HandlerType,
+		env.Message.MessageDescription(),
+	)
+}
+
+// suggestVerifyingHandlers adds a suggestion to s to verify the logic within
+// each of the handlers that produced the messages in envs.
+func suggestVerifyingHandlers(s *ReportSection, envs []*envelope.Envelope) {
+	var handlers []string
+	types := map[string]configkit.HandlerType{}
+
+	for _, env := range envs {
+		if env.Origin == nil {
+			continue
+		}
+
+		n := env.Origin.Handler.Identity().Name
+		if _, ok := types[n]; !ok {
+			handlers = append(handlers, n)
+			types[n] = env.Origin.HandlerType
+		}
+	}
+
+	for _, n := range handlers {
+		s.AppendListItem("verify the logic within the '%s' %s message handler", n, types[n])
+	}
+}
+
+// producedEnvelope returns the envelope containing the message that was
+// produced by a handler, if f is a fact that describes such a message.
+func producedEnvelope(f fact.Fact) (*envelope.Envelope, bool) {
+	switch x := f.(type) {
+	case fact.EventRecordedByAggregate:
+		return x.EventEnvelope, true
+	case fact.EventRecordedByIntegration:
+		return x.EventEnvelope, true
+	case fact.CommandExecutedByProcess:
+		return x.CommandEnvelope, true
+	case fact.TimeoutScheduledByProcess:
+		return x.TimeoutEnvelope, true
+	}
+
+	return nil, false
+}
+
+// handlerScope returns a copy of s for use by a child expectation that is
+// only ever offered messages produced by handlers.
+func handlerScope(s PredicateScope) PredicateScope {
+	// Messages that are passed to the engine directly are never produced by a
+	// handler, so there is no sense in the child matching them.
+	s.Options.MatchDispatchCycleStartedFacts = false
+	return s
+}
+
+// filteredPredicate is a Predicate for a child expectation that is only
+// notified of some of the messages that are produced.
+//
+// It is used by expectations such as FromHandler() and CausedBy() to explain
+// the failure when the expected message is produced, but is filtered out.
+type filteredPredicate struct {
+	// child is notified of the facts that pass the filter, whereas unfiltered
+	// is notified of every fact.
+	child      Predicate
+	unfiltered Predicate
+
+	// rejected is the envelope of the message that caused unfiltered to be
+	// met, if that message did not pass the filter.
+	rejected *envelope.Envelope
+}
+
+// newFilteredPredicate returns a new filteredPredicate for the child
+// expectation e.
+func newFilteredPredicate(s PredicateScope, e Expectation) (*filteredPredicate, error) {
+	unfiltered, err := e.Predicate(s)
+	if err != nil {
+		return nil, err
+	}
+
+	child, err := e.Predicate(handlerScope(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return &filteredPredicate{
+		child:      child,
+		unfiltered: unfiltered,
+	}, nil
+}
+
+// NotifyUnfiltered notifies the unfiltered predicate of f.
+//
+// accept is the filter applied to the messages offered to the child. The
+// caller is responsible for notifying the child itself.
+func (p *filteredPredicate) NotifyUnfiltered(
+	f fact.Fact,
+	accept func(*envelope.Envelope) bool,
+) {
+	if p.unfiltered.Ok() {
+		return
+	}
+
+	p.unfiltered.Notify(f)
+
+	env, ok := producedEnvelope(f)
+	if x, isCycle := f.(fact.DispatchCycleBegun); isCycle {
+		env, ok = x.Envelope, true
+	}
+
+	if ok && p.unfiltered.Ok() && !accept(env) {
+		p.rejected = env
+	}
+}
+
+// NotifyChild notifies the child predicate of f.
+func (p *filteredPredicate) NotifyChild(f fact.Fact) {
+	p.child.Notify(f)
+}
+
+// Ok returns true if the child predicate is ok.
+func (p *filteredPredicate) Ok() bool {
+	return p.child.Ok()
+}
+
+// Done is called when the action has completed.
+func (p *filteredPredicate) Done() {
+	p.child.Done()
+	p.unfiltered.Done()
+}
+
+// Report returns the child's report.
+//
+// If the child failed only because the matching message was filtered out, it
+// also returns the envelope of that message, in which case the child's
+// explanation and suggestions have been removed from the report, as they are
+// not relevant.
+func (p *filteredPredicate) Report(ctx ReportGenerationContext) (*Report, *envelope.Envelope) {
+	rep := p.child.Report(ctx)
+
+	if rep.Ok || ctx.TreeOk || ctx.IsInverted || p.rejected == nil {
+		return rep, nil
+	}
+
+	// The child would have been met if not for the filtering, so the child's
+	// own explanation and suggestions are not relevant.
+	rep.Explanation = ""
+	rep.Sections = nil
+
+	return rep, p.rejected
+}
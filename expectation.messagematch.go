@@ -115,6 +115,37 @@ func ToOnlyRecordEventsMatching[T dogma.Event](
 	}
 }
 
+// ToScheduleTimeoutMatching returns an expectation that passes if a timeout is
+// scheduled that satisfies the given predicate function.
+//
+// Always prefer using [ToScheduleTimeout] instead, if possible, as it provides
+// more meaningful information in the result of a failure.
+//
+// pred is the predicate function. It is called for each scheduled timeout. It
+// must return nil at least once for the expectation to pass.
+//
+// pred may return the [IgnoreMessage] error to indicate that the predicate does
+// not apply to a specific message. Any timeout that is not of type T is also
+// ignored.
+//
+// The options may be used to constrain the time at which the timeout is
+// scheduled. A timeout that satisfies pred but not the options is reported as
+// a failed match.
+func ToScheduleTimeoutMatching[T dogma.Timeout](
+	pred func(T) error,
+	options ...ScheduleOption,
+) Expectation {
+	if pred == nil {
+		panic("ToScheduleTimeoutMatching(<nil>): function must not be nil")
+	}
+
+	return &messageMatchExpectation[T]{
+		pred:       pred,
+		exhaustive: false,
+		schedule:   newScheduleConstraints(options),
+	}
+}
+
 // IgnoreMessage is an error that can be returned by predicate functions to
 // indicate that the predicate does not care about the message and therefore the
 // predicate's result should not affect the expectation's result.
@@ -124,11 +155,12 @@ var IgnoreMessage = errors.New("this message does not need to be inspected by th
 // message that satisfies a predicate function is produced.
 //
 // It is the implementation used by [ToExecuteCommandMatching],
-// [ToRecordEventMatching], [ToOnlyExecuteCommandsMatching], and
-// [ToOnlyRecordEventsMatching].
+// [ToRecordEventMatching], [ToScheduleTimeoutMatching],
+// [ToOnlyExecuteCommandsMatching], and [ToOnlyRecordEventsMatching].
 type messageMatchExpectation[T dogma.Message] struct {
 	pred       func(T) error
 	exhaustive bool
+	schedule   scheduleConstraints
 }
 
 func (e *messageMatchExpectation[T]) Caption() string {
@@ -142,8 +174,9 @@ func (e *messageMatchExpectation[T]) Caption() string {
 
 	return inflect.Sprintf(
 		message.KindFor[T](),
-		"to <produce> a <message> that matches the predicate near %s",
+		"to <produce> a <message> that matches the predicate near %s%s",
 		location.OfFunc(e.pred),
+		e.schedule,
 	)
 }
 
@@ -158,6 +191,7 @@ func (e *messageMatchExpectation[T]) Predicate(s PredicateScope) (Predicate, err
 	return &messageMatchPredicate[T]{
 		pred:       e.pred,
 		exhaustive: e.exhaustive,
+		schedule:   e.schedule,
 		tracker: tracker{
 			kind:    message.KindFor[T](),
 			options: s.Options,
@@ -170,6 +204,7 @@ func (e *messageMatchExpectation[T]) Predicate(s PredicateScope) (Predicate, err
 type messageMatchPredicate[T dogma.Message] struct {
 	pred       func(T) error
 	exhaustive bool
+	schedule   scheduleConstraints
 	failures   []*failedMatch
 	matched    int
 	ignored    int
@@ -201,6 +236,10 @@ func (p *messageMatchPredicate[T]) messageProduced(env *envelope.Envelope) {
 	var err error
 	if m, ok := env.Message.(T); ok {
 		err = p.pred(m)
+
+		if err == nil && !p.schedule.IsSatisfiedBy(env) {
+			err = errors.New(p.schedule.Violation(env))
+		}
 	} else if p.exhaustive {
 		err = fmt.Errorf("predicate function expected %s", expectedType)
 	} else {
@@ -260,8 +299,9 @@ func (p *messageMatchPredicate[T]) Report(ctx ReportGenerationContext) *Report {
 		Ok:     p.ok,
 		Criteria: inflect.Sprintf(
 			k,
-			"<produce> a <message> that matches the predicate near %s",
+			"<produce> a <message> that matches the predicate near %s%s",
 			location.OfFunc(p.pred),
+			p.schedule,
 		),
 	}
 
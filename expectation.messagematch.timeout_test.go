@@ -0,0 +1,133 @@
+package testkit_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToScheduleTimeoutMatching()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		EventThatSchedulesTimeout = EventStub[TypeT]
+		TimeoutThatIsScheduled    = TimeoutStub[TypeT]
+	)
+
+	startTime := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)
+	scheduledFor := startTime.Add(1 * time.Hour)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "0d3a6a0c-6a0f-4a9b-a8a5-5c3f7b3e2d61")
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "8a5e0c32-1f0e-4d5f-9f6d-3c0b7e9b2a44")
+						c.Routes(
+							dogma.HandlesEvent[EventThatSchedulesTimeout](),
+							dogma.SchedulesTimeout[TimeoutThatIsScheduled](),
+							dogma.ExecutesCommand[CommandStub[TypeC]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(
+						context.Context,
+						dogma.Event,
+					) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						m dogma.Event,
+					) error {
+						s.ScheduleTimeout(
+							TimeoutThatIsScheduled{
+								Content: m.(EventThatSchedulesTimeout).Content,
+							},
+							scheduledFor,
+						)
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.It("passes if a matching timeout is scheduled at an acceptable time", func() {
+		test := Begin(testingT, app, StartTimeAt(startTime))
+		test.Expect(
+			RecordEvent(EventThatSchedulesTimeout{Content: "<content>"}),
+			ToScheduleTimeoutMatching(
+				func(m TimeoutThatIsScheduled) error {
+					if m.Content == "<content>" {
+						return nil
+					}
+					return errors.New("<error>")
+				},
+				ScheduledAfter(startTime),
+			),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeFalse())
+	})
+
+	g.It("reports a failed match if a matching timeout is scheduled at the wrong time", func() {
+		test := Begin(testingT, app, StartTimeAt(startTime))
+		test.Expect(
+			RecordEvent(EventThatSchedulesTimeout{Content: "<content>"}),
+			ToScheduleTimeoutMatching(
+				func(m TimeoutThatIsScheduled) error {
+					return nil
+				},
+				ScheduledFor(startTime),
+			),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElements(
+			`  | FAILED MATCHES`,
+			`  |     • stubs.TimeoutStub[TypeT]: scheduled for 2100-01-02T04:04:05Z, expected for 2100-01-02T03:04:05Z`,
+		))
+	})
+
+	g.It("reports errors returned by the predicate function", func() {
+		test := Begin(testingT, app, StartTimeAt(startTime))
+		test.Expect(
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeoutMatching(
+				func(m TimeoutThatIsScheduled) error {
+					return errors.New("<error>")
+				},
+			),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElements(
+			`  | FAILED MATCHES`,
+			`  |     • stubs.TimeoutStub[TypeT]: <error>`,
+		))
+	})
+
+	g.It("panics if the function is nil", func() {
+		gm.Expect(func() {
+			ToScheduleTimeoutMatching[TimeoutThatIsScheduled](nil)
+		}).To(gm.PanicWith("ToScheduleTimeoutMatching(<nil>): function must not be nil"))
+	})
+})
@@ -3,6 +3,7 @@ package testkit
 import (
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
 	"github.com/dogmatiq/testkit/fact"
 	"github.com/dogmatiq/testkit/internal/inflect"
 )
@@ -51,20 +52,34 @@ func ToRecordEventOfType(m dogma.Event) Expectation {
 	}
 }
 
+// ToScheduleTimeoutType returns an expectation that passes if a timeout of
+// type T is scheduled.
+//
+// The options may be used to constrain the time at which the timeout is
+// scheduled.
+func ToScheduleTimeoutType[T dogma.Timeout](options ...ScheduleOption) Expectation {
+	return &messageTypeExpectation{
+		expectedType: message.TypeFor[T](),
+		schedule:     newScheduleConstraints(options),
+	}
+}
+
 // messageTypeExpectation is an Expectation that checks that a message of a
 // specific type is produced.
 //
-// It is the implementation used by ToExecuteCommandOfType() and
-// ToRecordEventOfType().
+// It is the implementation used by ToExecuteCommandOfType(),
+// ToRecordEventOfType() and ToScheduleTimeoutType().
 type messageTypeExpectation struct {
 	expectedType message.Type
+	schedule     scheduleConstraints
 }
 
 func (e *messageTypeExpectation) Caption() string {
 	return inflect.Sprintf(
 		e.expectedType.Kind(),
-		"to <produce> a <message> of type %s",
+		"to <produce> a <message> of type %s%s",
 		e.expectedType,
+		e.schedule,
 	)
 }
 
@@ -75,6 +90,7 @@ func (e *messageTypeExpectation) Predicate(s PredicateScope) (Predicate, error)
 
 	return &messageTypePredicate{
 		expectedType: e.expectedType,
+		schedule:     e.schedule,
 		tracker: tracker{
 			kind:    e.expectedType.Kind(),
 			options: s.Options,
@@ -86,7 +102,9 @@ func (e *messageTypeExpectation) Predicate(s PredicateScope) (Predicate, error)
 // messageTypeExpectation.
 type messageTypePredicate struct {
 	expectedType message.Type
+	schedule     scheduleConstraints
 	ok           bool
+	bestMatch    *envelope.Envelope
 	tracker      tracker
 }
 
@@ -95,9 +113,13 @@ func (p *messageTypePredicate) Notify(f fact.Fact) {
 		return
 	}
 
-	if env, ok := p.tracker.Notify(f); ok {
-		p.ok = message.TypeOf(env.Message) == p.expectedType
+	env, ok := p.tracker.Notify(f)
+	if !ok || message.TypeOf(env.Message) != p.expectedType {
+		return
 	}
+
+	p.bestMatch = env
+	p.ok = p.schedule.IsSatisfiedBy(env)
 }
 
 func (p *messageTypePredicate) Ok() bool {
@@ -113,8 +135,9 @@ func (p *messageTypePredicate) Report(ctx ReportGenerationContext) *Report {
 		Ok:     p.ok,
 		Criteria: inflect.Sprintf(
 			p.expectedType.Kind(),
-			"<produce> any '%s' <message>",
+			"<produce> any '%s' <message>%s",
 			p.expectedType,
+			p.schedule,
 		),
 	}
 
@@ -122,6 +145,19 @@ func (p *messageTypePredicate) Report(ctx ReportGenerationContext) *Report {
 		return rep
 	}
 
+	if p.bestMatch != nil {
+		rep.Explanation = inflect.Sprintf(
+			p.expectedType.Kind(),
+			"a <message> of this type was <produced> by the '%s' %s message handler, but it was %s",
+			p.bestMatch.Origin.Handler.Identity().Name,
+			p.bestMatch.Origin.HandlerType,
+			p.schedule.Violation(p.bestMatch),
+		)
+
+		rep.Section(suggestionsSection).AppendListItem("check the time at which the timeout is scheduled")
+		return rep
+	}
+
 	reportNoMatch(rep, &p.tracker)
 	return rep
 }
@@ -0,0 +1,168 @@
+package testkit_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToScheduleTimeoutType()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		EventThatIsIgnored        = EventStub[TypeX]
+		EventThatSchedulesTimeout = EventStub[TypeT]
+
+		TimeoutThatIsScheduled      = TimeoutStub[TypeT]
+		TimeoutThatIsNeverScheduled = TimeoutStub[TypeX]
+	)
+
+	startTime := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)
+	scheduledFor := startTime.Add(1 * time.Hour)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "4d3b1fa0-0b8f-4c3c-a0c3-6f6bd4c1f1a1")
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "e2b0b3f1-5b0a-4a93-9a0e-0f6d1b5a3c17")
+						c.Routes(
+							dogma.HandlesEvent[EventThatIsIgnored](),
+							dogma.HandlesEvent[EventThatSchedulesTimeout](),
+							dogma.SchedulesTimeout[TimeoutThatIsScheduled](),
+							dogma.ExecutesCommand[CommandStub[TypeC]](),
+							dogma.SchedulesTimeout[TimeoutThatIsNeverScheduled](),
+						)
+					},
+					RouteEventToInstanceFunc: func(
+						context.Context,
+						dogma.Event,
+					) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						m dogma.Event,
+					) error {
+						if _, ok := m.(EventThatSchedulesTimeout); ok {
+							s.ScheduleTimeout(TimeoutThatIsScheduled{}, scheduledFor)
+						}
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app, StartTimeAt(startTime))
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"timeout type scheduled as expected",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeoutType[TimeoutThatIsScheduled](),
+			expectPass,
+			expectReport(
+				`✓ schedule any 'stubs.TimeoutStub[TypeT]' timeout`,
+			),
+		),
+		g.Entry(
+			"timeout type scheduled for the expected time",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeoutType[TimeoutThatIsScheduled](
+				ScheduledFor(scheduledFor),
+			),
+			expectPass,
+			expectReport(
+				`✓ schedule any 'stubs.TimeoutStub[TypeT]' timeout for 2100-01-02T04:04:05Z`,
+			),
+		),
+		g.Entry(
+			"timeout type scheduled for the wrong time",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeoutType[TimeoutThatIsScheduled](
+				ScheduledBefore(scheduledFor),
+			),
+			expectFail,
+			expectReport(
+				`✗ schedule any 'stubs.TimeoutStub[TypeT]' timeout before 2100-01-02T04:04:05Z`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a timeout of this type was scheduled by the '<process>' process message handler, but it was scheduled for 2100-01-02T04:04:05Z, expected before 2100-01-02T04:04:05Z`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the time at which the timeout is scheduled`,
+			),
+		),
+		g.Entry(
+			"no matching timeout types scheduled",
+			RecordEvent(EventThatSchedulesTimeout{}),
+			ToScheduleTimeoutType[TimeoutThatIsNeverScheduled](),
+			expectFail,
+			expectReport(
+				`✗ schedule any 'stubs.TimeoutStub[TypeX]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     none of the engaged handlers scheduled a matching timeout`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"no messages produced at all",
+			RecordEvent(EventThatIsIgnored{}),
+			ToScheduleTimeoutType[TimeoutThatIsScheduled](),
+			expectFail,
+			expectReport(
+				`✗ schedule any 'stubs.TimeoutStub[TypeT]' timeout`,
+				``,
+				`  | EXPLANATION`,
+				`  |     no messages were produced at all`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+	)
+
+	g.It("fails the test if the message type is unrecognized", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToScheduleTimeoutType[TimeoutStub[TypeU]](),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"a timeout of type stubs.TimeoutStub[TypeU] can never be scheduled, the application does not use this message type",
+		))
+	})
+})
@@ -0,0 +1,195 @@
+package testkit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/inflect"
+)
+
+const (
+	// producedMessagesSection is the heading for the section of the test
+	// report that lists the messages that were produced contrary to the
+	// expectations of ToProduceNothing() or ToProduceNoMessagesOfKind().
+	producedMessagesSection = "Produced Messages"
+)
+
+// ToProduceNothing returns an expectation that passes if no messages of any
+// kind are produced by the application's handlers.
+//
+// Messages that are passed to the engine directly by the action itself, such
+// as the command passed to ExecuteCommand(), are not considered to be produced
+// by the application.
+func ToProduceNothing(options ...NoMessagesOption) Expectation {
+	return newNoMessagesExpectation(nil, options)
+}
+
+// ToProduceNoMessagesOfKind returns an expectation that passes if no messages
+// of kind k are produced by the application's handlers.
+//
+// Messages that are passed to the engine directly by the action itself are not
+// considered to be produced by the application, in the same manner as
+// ToProduceNothing().
+func ToProduceNoMessagesOfKind(k message.Kind, options ...NoMessagesOption) Expectation {
+	return newNoMessagesExpectation(&k, options)
+}
+
+// NoMessagesOption is an option that changes the behavior of
+// [ToProduceNothing] and [ToProduceNoMessagesOfKind].
+type NoMessagesOption interface {
+	applyNoMessagesOption(*noMessagesExpectation)
+}
+
+type noMessagesOptionFunc func(*noMessagesExpectation)
+
+func (f noMessagesOptionFunc) applyNoMessagesOption(e *noMessagesExpectation) {
+	f(e)
+}
+
+// Ignoring returns a NoMessagesOption that permits messages of type T to be
+// produced.
+//
+// It is typically used to ignore "noise", such as events that are only
+// consumed by projections.
+func Ignoring[T dogma.Message]() NoMessagesOption {
+	return noMessagesOptionFunc(func(e *noMessagesExpectation) {
+		t := message.TypeFor[T]()
+
+		if !slices.Contains(e.ignored, t) {
+			e.ignored = append(e.ignored, t)
+		}
+	})
+}
+
+func newNoMessagesExpectation(
+	k *message.Kind,
+	options []NoMessagesOption,
+) *noMessagesExpectation {
+	e := &noMessagesExpectation{
+		kind: k,
+	}
+
+	for _, opt := range options {
+		opt.applyNoMessagesOption(e)
+	}
+
+	return e
+}
+
+// noMessagesExpectation is an Expectation that checks that no messages are
+// produced.
+//
+// It is the implementation used by ToProduceNothing() and
+// ToProduceNoMessagesOfKind().
+type noMessagesExpectation struct {
+	kind    *message.Kind // nil if messages of any kind are unexpected
+	ignored []message.Type
+}
+
+func (e *noMessagesExpectation) Caption() string {
+	return "to " + e.criteria()
+}
+
+// criteria returns a description of the expectation's requirement to pass.
+func (e *noMessagesExpectation) criteria() string {
+	c := "produce no messages"
+	if e.kind != nil {
+		c = inflect.Sprint(*e.kind, "<produce> no <messages>")
+	}
+
+	if len(e.ignored) == 0 {
+		return c
+	}
+
+	var names []string
+	for _, t := range e.ignored {
+		names = append(names, t.String())
+	}
+
+	return fmt.Sprintf(
+		"%s (ignoring %s)",
+		c,
+		strings.Join(names, ", "),
+	)
+}
+
+func (e *noMessagesExpectation) Predicate(PredicateScope) (Predicate, error) {
+	return &noMessagesPredicate{
+		expectation: e,
+	}, nil
+}
+
+// noMessagesPredicate is the Predicate implementation for
+// noMessagesExpectation.
+type noMessagesPredicate struct {
+	expectation *noMessagesExpectation
+	produced    []*envelope.Envelope
+}
+
+func (p *noMessagesPredicate) Notify(f fact.Fact) {
+	env, ok := producedEnvelope(f)
+	if !ok {
+		return
+	}
+
+	e := p.expectation
+	t := message.TypeOf(env.Message)
+
+	if e.kind != nil && t.Kind() != *e.kind {
+		return
+	}
+
+	if slices.Contains(e.ignored, t) {
+		return
+	}
+
+	p.produced = append(p.produced, env)
+}
+
+func (p *noMessagesPredicate) Ok() bool {
+	return len(p.produced) == 0
+}
+
+func (p *noMessagesPredicate) Done() {
+}
+
+func (p *noMessagesPredicate) Report(ctx ReportGenerationContext) *Report {
+	e := p.expectation
+
+	rep := &Report{
+		TreeOk:   ctx.TreeOk,
+		Ok:       p.Ok(),
+		Criteria: e.criteria(),
+	}
+
+	if rep.Ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	n := len(p.produced)
+
+	switch {
+	case e.kind != nil && n == 1:
+		rep.Explanation = inflect.Sprint(*e.kind, "1 <message> was <produced>")
+	case e.kind != nil:
+		rep.Explanation = inflect.Sprintf(*e.kind, "%d <messages> were <produced>", n)
+	case n == 1:
+		rep.Explanation = "1 message was produced"
+	default:
+		rep.Explanation = fmt.Sprintf("%d messages were produced", n)
+	}
+
+	list := rep.Section(producedMessagesSection)
+	for _, env := range p.produced {
+		list.AppendListItem("%s", describeEnvelope(env))
+	}
+
+	suggestVerifyingHandlers(rep.Section(suggestionsSection), p.produced)
+
+	return rep
+}
@@ -0,0 +1,201 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToProduceNothing() and ToProduceNoMessagesOfKind()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "37f4b8d9-0517-439c-a849-d7e8f90a1b2c")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "4805c9ea-1628-44ad-b95a-e8f90a1b2c3d")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+							dogma.HandlesCommand[CommandStub[TypeC]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeB]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						switch m.(type) {
+						case CommandStub[TypeA]:
+							s.RecordEvent(EventA1)
+						case CommandStub[TypeB]:
+							s.RecordEvent(EventB1)
+						}
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "5916dafb-2739-45be-8a6b-f90a1b2c3d4e")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeB]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ExecuteCommand(CommandB1)
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"ToProduceNothing() passes when no messages are produced",
+			ExecuteCommand(CommandC1),
+			ToProduceNothing(),
+			expectPass,
+			expectReport(
+				`✓ produce no messages`,
+			),
+		),
+		g.Entry(
+			"ToProduceNothing() fails when messages are produced",
+			ExecuteCommand(CommandA1),
+			ToProduceNothing(),
+			expectFail,
+			expectReport(
+				`✗ produce no messages`,
+				``,
+				`  | EXPLANATION`,
+				`  |     3 messages were produced`,
+				`  | `,
+				`  | PRODUCED MESSAGES`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+				`  |     • stubs.EventStub[TypeB] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeB:B1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"ToProduceNothing() passes when only ignored messages are produced",
+			ExecuteCommand(CommandA1),
+			ToProduceNothing(
+				Ignoring[EventStub[TypeA]](),
+				Ignoring[EventStub[TypeB]](),
+				Ignoring[CommandStub[TypeB]](),
+			),
+			expectPass,
+			expectReport(
+				`✓ produce no messages (ignoring stubs.EventStub[TypeA], stubs.EventStub[TypeB], stubs.CommandStub[TypeB])`,
+			),
+		),
+		g.Entry(
+			"ToProduceNothing() fails when messages other than those ignored are produced",
+			ExecuteCommand(CommandA1),
+			ToProduceNothing(
+				Ignoring[EventStub[TypeA]](),
+				Ignoring[EventStub[TypeB]](),
+			),
+			expectFail,
+			expectReport(
+				`✗ produce no messages (ignoring stubs.EventStub[TypeA], stubs.EventStub[TypeB])`,
+				``,
+				`  | EXPLANATION`,
+				`  |     1 message was produced`,
+				`  | `,
+				`  | PRODUCED MESSAGES`,
+				`  |     • stubs.CommandStub[TypeB] command executed by the '<process>' process message handler: command(stubs.TypeB:B1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"ToProduceNoMessagesOfKind() passes when no messages of the kind are produced",
+			ExecuteCommand(CommandA1),
+			ToProduceNoMessagesOfKind(message.TimeoutKind),
+			expectPass,
+			expectReport(
+				`✓ schedule no timeouts`,
+			),
+		),
+		g.Entry(
+			"ToProduceNoMessagesOfKind() fails when messages of the kind are produced",
+			ExecuteCommand(CommandA1),
+			ToProduceNoMessagesOfKind(message.EventKind),
+			expectFail,
+			expectReport(
+				`✗ record no events`,
+				``,
+				`  | EXPLANATION`,
+				`  |     2 events were recorded`,
+				`  | `,
+				`  | PRODUCED MESSAGES`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.EventStub[TypeB] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeB:B1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"ToProduceNoMessagesOfKind() passes when only ignored messages of the kind are produced",
+			ExecuteCommand(CommandA1),
+			ToProduceNoMessagesOfKind(
+				message.CommandKind,
+				Ignoring[CommandStub[TypeB]](),
+			),
+			expectPass,
+			expectReport(
+				`✓ execute no commands (ignoring stubs.CommandStub[TypeB])`,
+			),
+		),
+	)
+})
@@ -0,0 +1,243 @@
+package testkit
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/internal/inflect"
+)
+
+// FromHandler is an expectation that passes if e passes when only the
+// messages produced by the handler named n are considered.
+//
+// It is used to assert that a message is produced by a specific handler, and
+// not by some other handler that behaves similarly.
+func FromHandler(n string, e Expectation) Expectation {
+	if n == "" {
+		panic(fmt.Sprintf("FromHandler(%#v, ...): handler name must not be empty", n))
+	}
+
+	if e == nil {
+		panic(fmt.Sprintf("FromHandler(%#v, <nil>): expectation must not be nil", n))
+	}
+
+	return &originExpectation{
+		handler: n,
+		child:   e,
+	}
+}
+
+// FromInstance is an expectation that passes if e passes when only the
+// messages produced by the aggregate or process instance with the given ID
+// are considered.
+//
+// It is used to assert that a message is produced by a specific instance, and
+// not by some other instance that behaves similarly.
+func FromInstance(handler, id string, e Expectation) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("FromInstance(%#v, %#v, ...): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("FromInstance(%#v, %#v, ...): instance ID must not be empty", handler, id))
+	}
+
+	if e == nil {
+		panic(fmt.Sprintf("FromInstance(%#v, %#v, <nil>): expectation must not be nil", handler, id))
+	}
+
+	return &originExpectation{
+		handler:    handler,
+		instanceID: id,
+		child:      e,
+	}
+}
+
+// originExpectation is an Expectation that only considers the messages
+// produced by a specific handler or instance.
+//
+// It is the implementation used by FromHandler() and FromInstance().
+type originExpectation struct {
+	handler    string
+	instanceID string // empty if any instance is permitted
+	child      Expectation
+}
+
+func (e *originExpectation) Caption() string {
+	if e.instanceID == "" {
+		return fmt.Sprintf(
+			"%s via the '%s' handler",
+			e.child.Caption(),
+			e.handler,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s via the '%s' instance of the '%s' handler",
+		e.child.Caption(),
+		e.instanceID,
+		e.handler,
+	)
+}
+
+func (e *originExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	h, err := lookupHandler(s.App, e.handler)
+	if err != nil {
+		return nil, err
+	}
+
+	ht := h.HandlerType()
+
+	if e.instanceID != "" &&
+		ht != configkit.AggregateHandlerType &&
+		ht != configkit.ProcessHandlerType {
+		return nil, fmt.Errorf(
+			"'%s' is %s %s message handler, only aggregate and process message handlers have instances",
+			e.handler,
+			article(ht),
+			ht,
+		)
+	}
+
+	p, err := newFilteredPredicate(s, e.child)
+	if err != nil {
+		return nil, err
+	}
+
+	return &originPredicate{
+		expectation: e,
+		handlerType: ht,
+		child:       p,
+	}, nil
+}
+
+// originPredicate is the Predicate implementation for originExpectation.
+type originPredicate struct {
+	expectation *originExpectation
+	handlerType configkit.HandlerType
+
+	// child is notified of the facts that relate to the expected handler or
+	// instance.
+	child *filteredPredicate
+}
+
+func (p *originPredicate) Notify(f fact.Fact) {
+	p.child.NotifyUnfiltered(f, func(env *envelope.Envelope) bool {
+		return p.isExpectedOrigin(env.Origin)
+	})
+
+	if p.isRelevant(f) {
+		p.child.NotifyChild(f)
+	}
+}
+
+// isRelevant returns true if f relates to the expected handler or instance.
+func (p *originPredicate) isRelevant(f fact.Fact) bool {
+	if env, ok := producedEnvelope(f); ok {
+		return p.isExpectedOrigin(env.Origin)
+	}
+
+	switch x := f.(type) {
+	case fact.HandlingBegun:
+		return x.Handler.Identity().Name == p.expectation.handler
+	case fact.HandlingCompleted:
+		return x.Handler.Identity().Name == p.expectation.handler
+	case fact.HandlingSkipped:
+		return x.Handler.Identity().Name == p.expectation.handler
+	}
+
+	return true
+}
+
+// isExpectedOrigin returns true if o describes the expected handler or
+// instance.
+func (p *originPredicate) isExpectedOrigin(o *envelope.Origin) bool {
+	if o == nil {
+		return false
+	}
+
+	if o.Handler.Identity().Name != p.expectation.handler {
+		return false
+	}
+
+	return p.expectation.instanceID == "" ||
+		o.InstanceID == p.expectation.instanceID
+}
+
+func (p *originPredicate) Ok() bool {
+	return p.child.Ok()
+}
+
+func (p *originPredicate) Done() {
+	p.child.Done()
+}
+
+func (p *originPredicate) Report(ctx ReportGenerationContext) *Report {
+	e := p.expectation
+	rep, elsewhere := p.child.Report(ctx)
+
+	if e.instanceID == "" {
+		rep.Criteria = fmt.Sprintf(
+			"%s via the '%s' %s",
+			rep.Criteria,
+			e.handler,
+			p.handlerType,
+		)
+	} else {
+		rep.Criteria = fmt.Sprintf(
+			"%s via the '%s' instance of the '%s' %s",
+			rep.Criteria,
+			e.instanceID,
+			e.handler,
+			p.handlerType,
+		)
+	}
+
+	if elsewhere == nil {
+		return rep
+	}
+
+	k := message.KindOf(elsewhere.Message)
+	o := elsewhere.Origin
+
+	switch {
+	case o == nil:
+		rep.Explanation = inflect.Sprint(
+			k,
+			"a matching <message> was <produced> via a <dispatcher>",
+		)
+	case o.InstanceID == "":
+		rep.Explanation = inflect.Sprintf(
+			k,
+			"a matching <message> was <produced> by the '%s' %s message handler",
+			o.Handler.Identity().Name,
+			o.HandlerType,
+		)
+	default:
+		rep.Explanation = inflect.Sprintf(
+			k,
+			"a matching <message> was <produced> by the '%s' instance of the '%s' %s message handler",
+			o.InstanceID,
+			o.Handler.Identity().Name,
+			o.HandlerType,
+		)
+	}
+
+	s := rep.Section(suggestionsSection)
+
+	if o != nil && o.Handler.Identity().Name == e.handler {
+		s.AppendListItem("verify that the correct instance ID is being tested")
+		s.AppendListItem("check the application's routing configuration")
+	}
+
+	s.AppendListItem(
+		"verify the logic within the '%s' %s message handler",
+		e.handler,
+		p.handlerType,
+	)
+
+	return rep
+}
@@ -0,0 +1,224 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func FromHandler() and FromInstance()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "c08d4162-9ea0-4c25-b1d2-60718293a4b5")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "d19e5273-afb1-4d36-82e3-718293a4b5c6")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<aggregate-instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						if m, ok := m.(CommandStub[TypeA]); ok {
+							s.RecordEvent(EventStub[TypeA]{Content: m.Content})
+						}
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "e2af6384-b0c2-4e47-93f4-8293a4b5c6d7")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeA]](),
+							dogma.ExecutesCommand[CommandStub[TypeB]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(_ context.Context, m dogma.Event) (string, bool, error) {
+						return "<instance-" + string(m.(EventStub[TypeA]).Content) + ">", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ExecuteCommand(CommandB1)
+						return nil
+					},
+				})
+
+				c.RegisterIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("<integration>", "f3b07495-c1d3-4f58-a405-93a4b5c6d7e8")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeC]](),
+							dogma.RecordsEvent[EventStub[TypeC]](),
+						)
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(ExecuteCommand(CommandA1), e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"FromHandler() passes when the message is produced by the handler",
+			FromHandler("<process>", ToExecuteCommand(CommandB1)),
+			expectPass,
+			expectReport(
+				`✓ execute a specific 'stubs.CommandStub[TypeB]' command via the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"FromHandler() fails when the message is produced by some other handler",
+			FromHandler("<aggregate>", ToExecuteCommand(CommandB1)),
+			expectFail,
+			expectReport(
+				`✗ execute a specific 'stubs.CommandStub[TypeB]' command via the '<aggregate>' aggregate`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a matching command was executed by the '<instance-A1>' instance of the '<process>' process message handler`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"FromInstance() passes when the message is produced by the instance",
+			FromInstance("<process>", "<instance-A1>", ToExecuteCommand(CommandB1)),
+			expectPass,
+			expectReport(
+				`✓ execute a specific 'stubs.CommandStub[TypeB]' command via the '<instance-A1>' instance of the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"FromInstance() fails when the message is produced by some other instance",
+			FromInstance("<process>", "<instance-A2>", ToExecuteCommand(CommandB1)),
+			expectFail,
+			expectReport(
+				`✗ execute a specific 'stubs.CommandStub[TypeB]' command via the '<instance-A2>' instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a matching command was executed by the '<instance-A1>' instance of the '<process>' process message handler`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the correct instance ID is being tested`,
+				`  |     • check the application's routing configuration`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"FromInstance() fails when the message is not produced at all",
+			FromInstance("<process>", "<instance-A1>", ToExecuteCommand(CommandB2)),
+			expectFail,
+			expectReport(
+				`✗ execute a specific 'stubs.CommandStub[TypeB]' command via the '<instance-A1>' instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a similar command was executed by the '<process>' process message handler`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the content of the message`,
+				`  | `,
+				`  | MESSAGE DIFF`,
+				`  |     stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB]{`,
+				`  |         Content:         "B[-2-]{+1+}"`,
+				`  |         ValidationError: ""`,
+				`  |     }`,
+			),
+		),
+	)
+
+	g.It("fails the test if the handler does not exist", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandA1),
+			FromHandler("<unknown>", ToExecuteCommand(CommandB1)),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"the '<app>' application does not have a handler named '<unknown>'",
+		))
+	})
+
+	g.It("fails the test if an instance of a handler without instances is expected", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandA1),
+			FromInstance("<integration>", "<instance>", ToRecordEvent(EventC1)),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"'<integration>' is an integration message handler, only aggregate and process message handlers have instances",
+		))
+	})
+
+	g.DescribeTable(
+		"it panics if the arguments are invalid",
+		func(fn func(), message string) {
+			gm.Expect(fn).To(gm.PanicWith(message))
+		},
+		g.Entry(
+			"FromHandler() with an empty handler name",
+			func() { FromHandler("", pass) },
+			`FromHandler("", ...): handler name must not be empty`,
+		),
+		g.Entry(
+			"FromHandler() with a nil expectation",
+			func() { FromHandler("<process>", nil) },
+			`FromHandler("<process>", <nil>): expectation must not be nil`,
+		),
+		g.Entry(
+			"FromInstance() with an empty handler name",
+			func() { FromInstance("", "<instance>", pass) },
+			`FromInstance("", "<instance>", ...): handler name must not be empty`,
+		),
+		g.Entry(
+			"FromInstance() with an empty instance ID",
+			func() { FromInstance("<process>", "", pass) },
+			`FromInstance("<process>", "", ...): instance ID must not be empty`,
+		),
+		g.Entry(
+			"FromInstance() with a nil expectation",
+			func() { FromInstance("<process>", "<instance>", nil) },
+			`FromInstance("<process>", "<instance>", <nil>): expectation must not be nil`,
+		),
+	)
+})
@@ -0,0 +1,400 @@
+package testkit
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+// ToBeginProcessInstance returns an expectation that passes if the process
+// message handler named handler begins the instance with the given ID.
+func ToBeginProcessInstance(handler, id string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToBeginProcessInstance(%#v, %#v): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("ToBeginProcessInstance(%#v, %#v): instance ID must not be empty", handler, id))
+	}
+
+	return &processInstanceExpectation{
+		handler:    handler,
+		instanceID: id,
+		change:     processInstanceBegun,
+	}
+}
+
+// ToBeginAnyProcessInstance returns an expectation that passes if the process
+// message handler named handler begins any instance.
+func ToBeginAnyProcessInstance(handler string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToBeginAnyProcessInstance(%#v): handler name must not be empty", handler))
+	}
+
+	return &processInstanceExpectation{
+		handler: handler,
+		change:  processInstanceBegun,
+	}
+}
+
+// ToEndProcessInstance returns an expectation that passes if the process
+// message handler named handler ends the instance with the given ID.
+//
+// The expectation does not pass if the handler reverts the ending by executing
+// a command or scheduling a timeout while handling the same message.
+func ToEndProcessInstance(handler, id string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToEndProcessInstance(%#v, %#v): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("ToEndProcessInstance(%#v, %#v): instance ID must not be empty", handler, id))
+	}
+
+	return &processInstanceExpectation{
+		handler:    handler,
+		instanceID: id,
+		change:     processInstanceEnded,
+	}
+}
+
+// ToEndAnyProcessInstance returns an expectation that passes if the process
+// message handler named handler ends any instance.
+//
+// The expectation does not pass if the handler reverts the ending by executing
+// a command or scheduling a timeout while handling the same message.
+func ToEndAnyProcessInstance(handler string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToEndAnyProcessInstance(%#v): handler name must not be empty", handler))
+	}
+
+	return &processInstanceExpectation{
+		handler: handler,
+		change:  processInstanceEnded,
+	}
+}
+
+// ToIgnoreEvent returns an expectation that passes if the process message
+// handler named handler chooses not to route an event to any instance.
+func ToIgnoreEvent(handler string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToIgnoreEvent(%#v): handler name must not be empty", handler))
+	}
+
+	return &processEventIgnoredExpectation{
+		handler: handler,
+	}
+}
+
+// The lifecycle changes of a process instance, as they appear in test reports.
+const (
+	processInstanceLoaded         = "loaded"
+	processInstanceNotFound       = "not found"
+	processInstanceBegun          = "begun"
+	processInstanceEnded          = "ended"
+	processInstanceEndingReverted = "ending reverted"
+)
+
+// processInstanceExpectation is an Expectation that checks that a process
+// instance is begun or ended.
+//
+// It is the implementation used by ToBeginProcessInstance(),
+// ToBeginAnyProcessInstance(), ToEndProcessInstance() and
+// ToEndAnyProcessInstance().
+type processInstanceExpectation struct {
+	handler    string
+	instanceID string // empty means "any instance"
+	change     string
+}
+
+func (e *processInstanceExpectation) Caption() string {
+	return "to " + e.criteria()
+}
+
+func (e *processInstanceExpectation) criteria() string {
+	verb := "begin"
+	if e.change == processInstanceEnded {
+		verb = "end"
+	}
+
+	if e.instanceID == "" {
+		return fmt.Sprintf(
+			"%s any instance of the '%s' process",
+			verb,
+			e.handler,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s the '%s' instance of the '%s' process",
+		verb,
+		e.instanceID,
+		e.handler,
+	)
+}
+
+func (e *processInstanceExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	if err := guardAgainstExpectationOnUnknownHandler(
+		s,
+		e.handler,
+		configkit.ProcessHandlerType,
+	); err != nil {
+		return nil, err
+	}
+
+	return &processInstancePredicate{
+		expectation: e,
+		tracker: instanceTracker{
+			handler: e.handler,
+		},
+	}, nil
+}
+
+// processInstancePredicate is the Predicate implementation for
+// processInstanceExpectation.
+type processInstancePredicate struct {
+	expectation *processInstanceExpectation
+	tracker     instanceTracker
+	ignored     int
+}
+
+func (p *processInstancePredicate) Notify(f fact.Fact) {
+	p.tracker.Notify(f)
+
+	switch x := f.(type) {
+	case fact.ProcessInstanceLoaded:
+		p.record(x.Handler, x.InstanceID, processInstanceLoaded)
+	case fact.ProcessInstanceNotFound:
+		p.record(x.Handler, x.InstanceID, processInstanceNotFound)
+	case fact.ProcessInstanceBegun:
+		p.record(x.Handler, x.InstanceID, processInstanceBegun)
+	case fact.ProcessInstanceEnded:
+		p.record(x.Handler, x.InstanceID, processInstanceEnded)
+	case fact.ProcessInstanceEndingReverted:
+		p.record(x.Handler, x.InstanceID, processInstanceEndingReverted)
+	case fact.ProcessEventIgnored:
+		if x.Handler.Identity().Name == p.expectation.handler {
+			p.ignored++
+		}
+	}
+}
+
+func (p *processInstancePredicate) record(h configkit.RichProcess, id, change string) {
+	if h.Identity().Name == p.expectation.handler {
+		p.tracker.record(id, change)
+	}
+}
+
+func (p *processInstancePredicate) Ok() bool {
+	// An ending only counts if it was not subsequently reverted while handling
+	// the same message.
+	if p.expectation.instanceID == "" {
+		return p.tracker.underwentAny(
+			p.expectation.change,
+			processInstanceEndingReverted,
+		)
+	}
+
+	return p.tracker.underwent(
+		p.expectation.instanceID,
+		p.expectation.change,
+		processInstanceEndingReverted,
+	)
+}
+
+func (p *processInstancePredicate) Done() {
+}
+
+func (p *processInstancePredicate) Report(ctx ReportGenerationContext) *Report {
+	ok := p.Ok()
+
+	rep := &Report{
+		TreeOk:   ctx.TreeOk,
+		Ok:       ok,
+		Criteria: p.expectation.criteria(),
+	}
+
+	if ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if p.tracker.reportNotEngaged(rep, configkit.ProcessHandlerType) {
+		return rep
+	}
+
+	p.tracker.buildInstancesSection(rep)
+
+	if p.expectation.instanceID == "" {
+		p.explainAny(rep)
+	} else {
+		p.explainSpecific(rep)
+	}
+
+	return rep
+}
+
+// explainAny populates the report's explanation and suggestions when the
+// expectation is not met for any instance.
+func (p *processInstancePredicate) explainAny(rep *Report) {
+	s := rep.Section(suggestionsSection)
+
+	if len(p.tracker.order) == 0 && p.ignored != 0 {
+		rep.Explanation = "the handler ignored all of the events it was engaged to handle"
+		s.AppendListItem(
+			"verify the logic within the RouteEventToInstance() method of the '%s' process message handler",
+			p.expectation.handler,
+		)
+		return
+	}
+
+	if p.expectation.change == processInstanceBegun {
+		rep.Explanation = "no instances were begun"
+		s.AppendListItem("verify that the events are routed to instances that do not already exist")
+	} else {
+		rep.Explanation = "no instances were ended"
+		s.AppendListItem("verify that the handler calls ProcessEventScope.End() or ProcessTimeoutScope.End()")
+	}
+
+	s.AppendListItem("verify the logic within the '%s' process message handler", p.expectation.handler)
+}
+
+// explainSpecific populates the report's explanation and suggestions when the
+// expectation is not met for a specific instance.
+func (p *processInstancePredicate) explainSpecific(rep *Report) {
+	s := rep.Section(suggestionsSection)
+	id := p.expectation.instanceID
+	others := p.tracker.others(id, p.expectation.change)
+
+	if len(others) != 0 {
+		if p.expectation.change == processInstanceBegun {
+			rep.Explanation = fmt.Sprintf("a different instance (%s) was begun", quoteIDs(others))
+		} else {
+			rep.Explanation = fmt.Sprintf("a different instance (%s) was ended", quoteIDs(others))
+		}
+
+		s.AppendListItem(
+			"verify the logic within the RouteEventToInstance() method of the '%s' process message handler",
+			p.expectation.handler,
+		)
+		return
+	}
+
+	if _, ok := p.tracker.history[id]; !ok {
+		rep.Explanation = fmt.Sprintf("the '%s' instance was not targeted by any message", id)
+		s.AppendListItem(
+			"verify the logic within the RouteEventToInstance() method of the '%s' process message handler",
+			p.expectation.handler,
+		)
+		return
+	}
+
+	switch p.expectation.change {
+	case processInstanceBegun:
+		rep.Explanation = "the instance already existed"
+		s.AppendListItem("verify that the instance is not begun by a prior action")
+	case processInstanceEnded:
+		if p.tracker.has(id, processInstanceEndingReverted) {
+			rep.Explanation = "the instance was ended, but the ending was reverted by executing a command or scheduling a timeout"
+			s.AppendListItem("verify that the handler does not produce messages after ending the instance")
+		} else {
+			rep.Explanation = "the instance was targeted, but it was not ended"
+			s.AppendListItem("verify the logic within the '%s' process message handler", p.expectation.handler)
+		}
+	}
+}
+
+// processEventIgnoredExpectation is an Expectation that checks that a process
+// message handler ignores an event.
+//
+// It is the implementation used by ToIgnoreEvent().
+type processEventIgnoredExpectation struct {
+	handler string
+}
+
+func (e *processEventIgnoredExpectation) Caption() string {
+	return fmt.Sprintf("to ignore an event within the '%s' process", e.handler)
+}
+
+func (e *processEventIgnoredExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	if err := guardAgainstExpectationOnUnknownHandler(
+		s,
+		e.handler,
+		configkit.ProcessHandlerType,
+	); err != nil {
+		return nil, err
+	}
+
+	return &processEventIgnoredPredicate{
+		handler: e.handler,
+		tracker: instanceTracker{
+			handler: e.handler,
+		},
+	}, nil
+}
+
+// processEventIgnoredPredicate is the Predicate implementation for
+// processEventIgnoredExpectation.
+type processEventIgnoredPredicate struct {
+	handler string
+	ok      bool
+	tracker instanceTracker
+}
+
+func (p *processEventIgnoredPredicate) Notify(f fact.Fact) {
+	p.tracker.Notify(f)
+
+	switch x := f.(type) {
+	case fact.ProcessEventIgnored:
+		if x.Handler.Identity().Name == p.handler {
+			p.ok = true
+		}
+	case fact.ProcessInstanceLoaded:
+		if x.Handler.Identity().Name == p.handler &&
+			message.KindOf(x.Envelope.Message) == message.EventKind {
+			p.tracker.record(x.InstanceID, processInstanceLoaded)
+		}
+	case fact.ProcessInstanceNotFound:
+		if x.Handler.Identity().Name == p.handler &&
+			message.KindOf(x.Envelope.Message) == message.EventKind {
+			p.tracker.record(x.InstanceID, processInstanceNotFound)
+		}
+	}
+}
+
+func (p *processEventIgnoredPredicate) Ok() bool {
+	return p.ok
+}
+
+func (p *processEventIgnoredPredicate) Done() {
+}
+
+func (p *processEventIgnoredPredicate) Report(ctx ReportGenerationContext) *Report {
+	rep := &Report{
+		TreeOk: ctx.TreeOk,
+		Ok:     p.ok,
+		Criteria: fmt.Sprintf(
+			"ignore an event within the '%s' process",
+			p.handler,
+		),
+	}
+
+	if p.ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if p.tracker.reportNotEngaged(rep, configkit.ProcessHandlerType) {
+		return rep
+	}
+
+	p.tracker.buildInstancesSection(rep)
+
+	rep.Explanation = "every event was routed to an instance"
+	rep.Section(suggestionsSection).AppendListItem(
+		"verify the logic within the RouteEventToInstance() method of the '%s' process message handler",
+		p.handler,
+	)
+
+	return rep
+}
@@ -0,0 +1,365 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/engine"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("process instance expectations", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		EventThatBegins         = EventStub[TypeB]
+		EventThatEnds           = EventStub[TypeE]
+		EventThatEndsAndReverts = EventStub[TypeR]
+		EventThatDoesNothing    = EventStub[TypeN]
+		EventThatIsIgnored      = EventStub[TypeI]
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "7c3f0c5e-8d0b-4f4e-a3a1-6b7f3c2d1e10")
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "b1d6a4f2-5e3c-4a7b-9d8e-0f1a2b3c4d5e")
+						c.Routes(
+							dogma.HandlesEvent[EventThatBegins](),
+							dogma.HandlesEvent[EventThatEnds](),
+							dogma.HandlesEvent[EventThatEndsAndReverts](),
+							dogma.HandlesEvent[EventThatDoesNothing](),
+							dogma.HandlesEvent[EventThatIsIgnored](),
+							dogma.ExecutesCommand[CommandStub[TypeA]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(
+						_ context.Context,
+						m dogma.Event,
+					) (string, bool, error) {
+						switch m := m.(type) {
+						case EventThatBegins:
+							return string(m.Content), true, nil
+						case EventThatEnds:
+							return string(m.Content), true, nil
+						case EventThatEndsAndReverts:
+							return string(m.Content), true, nil
+						case EventThatDoesNothing:
+							return string(m.Content), true, nil
+						case EventThatIsIgnored:
+							return "", false, nil
+						default:
+							panic(dogma.UnexpectedMessage)
+						}
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						m dogma.Event,
+					) error {
+						switch m.(type) {
+						case EventThatEnds:
+							s.End()
+						case EventThatEndsAndReverts:
+							s.End()
+							s.ExecuteCommand(CommandA1)
+						}
+						return nil
+					},
+				})
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<aggregate-instance>"
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			setup []Action,
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+			options ...TestOption,
+		) {
+			test := Begin(testingT, app, options...)
+			test.Prepare(setup...)
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"specific instance begun as expected",
+			nil,
+			RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			ToBeginProcessInstance("<process>", "<instance-1>"),
+			expectPass,
+			expectReport(
+				`✓ begin the '<instance-1>' instance of the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"any instance begun as expected",
+			nil,
+			RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			ToBeginAnyProcessInstance("<process>"),
+			expectPass,
+			expectReport(
+				`✓ begin any instance of the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"a different instance begun",
+			nil,
+			RecordEvent(EventThatBegins{Content: "<instance-2>"}),
+			ToBeginProcessInstance("<process>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ begin the '<instance-1>' instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     a different instance ('<instance-2>') was begun`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-2>: not found, begun`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the RouteEventToInstance() method of the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"instance already existed",
+			[]Action{
+				RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			},
+			RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			ToBeginProcessInstance("<process>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ begin the '<instance-1>' instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance already existed`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: loaded`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the instance is not begun by a prior action`,
+			),
+		),
+		g.Entry(
+			"event ignored rather than beginning an instance",
+			nil,
+			RecordEvent(EventThatIsIgnored{Content: "<instance-1>"}),
+			ToBeginAnyProcessInstance("<process>"),
+			expectFail,
+			expectReport(
+				`✗ begin any instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the handler ignored all of the events it was engaged to handle`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the RouteEventToInstance() method of the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"specific instance ended as expected",
+			[]Action{
+				RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			},
+			RecordEvent(EventThatEnds{Content: "<instance-1>"}),
+			ToEndProcessInstance("<process>", "<instance-1>"),
+			expectPass,
+			expectReport(
+				`✓ end the '<instance-1>' instance of the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"any instance ended as expected",
+			nil,
+			RecordEvent(EventThatEnds{Content: "<instance-1>"}),
+			ToEndAnyProcessInstance("<process>"),
+			expectPass,
+			expectReport(
+				`✓ end any instance of the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"ending reverted",
+			[]Action{
+				RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			},
+			RecordEvent(EventThatEndsAndReverts{Content: "<instance-1>"}),
+			ToEndProcessInstance("<process>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ end the '<instance-1>' instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance was ended, but the ending was reverted by executing a command or scheduling a timeout`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: loaded, ended, ending reverted`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the handler does not produce messages after ending the instance`,
+			),
+		),
+		g.Entry(
+			"instance targeted but not ended",
+			[]Action{
+				RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			},
+			RecordEvent(EventThatDoesNothing{Content: "<instance-1>"}),
+			ToEndProcessInstance("<process>", "<instance-1>"),
+			expectFail,
+			expectReport(
+				`✗ end the '<instance-1>' instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance was targeted, but it was not ended`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: loaded`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"no instances ended",
+			nil,
+			RecordEvent(EventThatDoesNothing{Content: "<instance-1>"}),
+			ToEndAnyProcessInstance("<process>"),
+			expectFail,
+			expectReport(
+				`✗ end any instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     no instances were ended`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: not found, begun`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the handler calls ProcessEventScope.End() or ProcessTimeoutScope.End()`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"event ignored as expected",
+			nil,
+			RecordEvent(EventThatIsIgnored{Content: "<instance-1>"}),
+			ToIgnoreEvent("<process>"),
+			expectPass,
+			expectReport(
+				`✓ ignore an event within the '<process>' process`,
+			),
+		),
+		g.Entry(
+			"event routed to an instance instead of being ignored",
+			nil,
+			RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			ToIgnoreEvent("<process>"),
+			expectFail,
+			expectReport(
+				`✗ ignore an event within the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     every event was routed to an instance`,
+				`  | `,
+				`  | INSTANCES`,
+				`  |     • <instance-1>: not found`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the RouteEventToInstance() method of the '<process>' process message handler`,
+			),
+		),
+		g.Entry(
+			"handler disabled",
+			nil,
+			RecordEvent(EventThatBegins{Content: "<instance-1>"}),
+			ToBeginAnyProcessInstance("<process>"),
+			expectFail,
+			expectReport(
+				`✗ begin any instance of the '<process>' process`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the '<process>' process message handler was not engaged`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • enable the '<process>' process message handler`,
+			),
+			WithUnsafeOperationOptions(
+				engine.EnableProcesses(false),
+			),
+		),
+	)
+
+	g.It("fails the test if the handler does not exist", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToIgnoreEvent("<unknown>"),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"the '<app>' application does not have a handler named '<unknown>'",
+		))
+	})
+
+	g.It("fails the test if the handler is not a process", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToEndAnyProcessInstance("<aggregate>"),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"'<aggregate>' is an aggregate message handler, not a process message handler",
+		))
+	})
+
+	g.It("panics if the handler name is empty", func() {
+		gm.Expect(func() {
+			ToBeginProcessInstance("", "<instance>")
+		}).To(gm.PanicWith(`ToBeginProcessInstance("", "<instance>"): handler name must not be empty`))
+	})
+
+	g.It("panics if the instance ID is empty", func() {
+		gm.Expect(func() {
+			ToEndProcessInstance("<process>", "")
+		}).To(gm.PanicWith(`ToEndProcessInstance("<process>", ""): instance ID must not be empty`))
+	})
+})
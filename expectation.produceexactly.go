@@ -0,0 +1,367 @@
+package testkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+const (
+	// unclaimedMessagesSection is the heading for the section of the test
+	// report that lists the messages that were produced but not claimed by
+	// any of the expectations passed to ToProduceExactly().
+	unclaimedMessagesSection = "Unclaimed Messages"
+)
+
+// ToProduceExactly returns an expectation that passes if all of its children
+// pass, and every message that is produced by the application's handlers is
+// "claimed" by one of the children.
+//
+// Each child claims a single message that meets it on its own, and each
+// message can only be claimed by a single child, so the same message must be
+// produced twice to satisfy two identical children. The messages are assigned
+// to the children such that as many children as possible are met, regardless
+// of the order in which the children are given.
+//
+// The children are typically expectations that match a single message, such as
+// ToRecordEvent() or ToExecuteCommandType(). A child may not be an expectation
+// that is met before the action is performed, such as Not(). Use
+// ToProduceNothing() on its own to expect that no messages are produced at
+// all.
+func ToProduceExactly(children ...Expectation) Expectation {
+	if len(children) == 0 {
+		panic("ToProduceExactly(): at least one child expectation must be provided")
+	}
+
+	return &produceExactlyExpectation{
+		children: children,
+	}
+}
+
+// produceExactlyExpectation is an Expectation that checks that the messages
+// produced by the application are exactly those described by its children.
+//
+// It is the implementation used by ToProduceExactly().
+type produceExactlyExpectation struct {
+	children []Expectation
+}
+
+func (e *produceExactlyExpectation) Caption() string {
+	return fmt.Sprintf(
+		"to produce exactly the messages described by %d expectations",
+		len(e.children),
+	)
+}
+
+func (e *produceExactlyExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	s = handlerScope(s)
+
+	for i, c := range e.children {
+		p, err := c.Predicate(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Ok() {
+			return nil, fmt.Errorf(
+				"ToProduceExactly(): expectation %d (%s) is met before the action is performed, so it can not claim a message",
+				i+1,
+				c.Caption(),
+			)
+		}
+	}
+
+	return &produceExactlyPredicate{
+		expectation: e,
+		scope:       s,
+	}, nil
+}
+
+// produceExactlyPredicate is the Predicate implementation for
+// produceExactlyExpectation.
+type produceExactlyPredicate struct {
+	expectation *produceExactlyExpectation
+	scope       PredicateScope
+
+	// facts contains every fact that does not describe a produced message,
+	// and produced contains the messages that were produced, in order.
+	facts    []fact.Fact
+	produced []*producedMessage
+
+	// results contains the predicate that describes the outcome of each
+	// child, and contested is true for each child that was not met only
+	// because the messages that it could claim were claimed by other
+	// children. They are populated once the action has completed.
+	results   []Predicate
+	contested []bool
+
+	// unclaimed contains the envelopes of the messages that were not claimed
+	// by any of the children.
+	unclaimed []*envelope.Envelope
+
+	done bool
+}
+
+// producedMessage is a message that was produced while the action was
+// performed.
+type producedMessage struct {
+	// Fact is the fact that describes the production of the message, and
+	// Envelope is the message's envelope.
+	Fact     fact.Fact
+	Envelope *envelope.Envelope
+
+	// Offset is the number of facts in produceExactlyPredicate.facts that
+	// occurred before the message was produced.
+	Offset int
+
+	// Predicates contains a predicate for each child that has been notified
+	// of this message and every fact that does not describe a produced
+	// message.
+	Predicates []Predicate
+}
+
+func (p *produceExactlyPredicate) Notify(f fact.Fact) {
+	if env, ok := producedEnvelope(f); ok {
+		// The messages can not be assigned to the children until every
+		// message is known, so each child is offered each message on its own.
+		m := &producedMessage{
+			Fact:       f,
+			Envelope:   env,
+			Offset:     len(p.facts),
+			Predicates: make([]Predicate, len(p.expectation.children)),
+		}
+
+		for i := range m.Predicates {
+			r := p.replay(i)
+			r.Notify(f)
+			m.Predicates[i] = r
+		}
+
+		p.produced = append(p.produced, m)
+		return
+	}
+
+	p.facts = append(p.facts, f)
+
+	for _, m := range p.produced {
+		for _, r := range m.Predicates {
+			r.Notify(f)
+		}
+	}
+}
+
+func (p *produceExactlyPredicate) Ok() bool {
+	// Unclaimed messages may be produced at any time until the action has
+	// completed.
+	return p.done && len(p.unclaimed) == 0 && p.failed() == 0
+}
+
+// failed returns the number of children that have not been met.
+func (p *produceExactlyPredicate) failed() int {
+	n := 0
+
+	for _, r := range p.results {
+		if !r.Ok() {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (p *produceExactlyPredicate) Done() {
+	children := p.expectation.children
+
+	// claims contains the messages that each child is met by on its own, as
+	// indices into p.produced.
+	claims := make([][]int, len(children))
+
+	for m, pm := range p.produced {
+		for i, r := range pm.Predicates {
+			if r.Ok() {
+				claims[i] = append(claims[i], m)
+			}
+		}
+	}
+
+	owners := assignClaims(claims, len(p.produced))
+	assigned := make([]int, len(children))
+	for i := range assigned {
+		assigned[i] = -1
+	}
+
+	var unclaimed []*producedMessage
+
+	for m, i := range owners {
+		if i >= 0 {
+			assigned[i] = m
+			continue
+		}
+
+		pm := p.produced[m]
+		unclaimed = append(unclaimed, pm)
+		p.unclaimed = append(p.unclaimed, pm.Envelope)
+	}
+
+	p.results = make([]Predicate, len(children))
+	p.contested = make([]bool, len(children))
+
+	for i := range children {
+		var r Predicate
+
+		if m := assigned[i]; m >= 0 {
+			r = p.produced[m].Predicates[i]
+		} else {
+			r = p.replay(i, unclaimed...)
+		}
+
+		r.Done()
+
+		p.results[i] = r
+		p.contested[i] = !r.Ok() && len(claims[i]) != 0
+	}
+
+	p.done = true
+}
+
+// replay returns a new predicate for the child at index i that has been
+// notified of every fact that does not describe a produced message, and of
+// the given produced messages, in the order in which they occurred.
+func (p *produceExactlyPredicate) replay(i int, produced ...*producedMessage) Predicate {
+	r, err := p.expectation.children[i].Predicate(p.scope)
+	if err != nil {
+		// This should never occur, as the same expectation has already
+		// produced a predicate using the same scope.
+		panic(err)
+	}
+
+	for j, f := range p.facts {
+		for len(produced) != 0 && produced[0].Offset == j {
+			r.Notify(produced[0].Fact)
+			produced = produced[1:]
+		}
+
+		r.Notify(f)
+	}
+
+	for _, m := range produced {
+		r.Notify(m.Fact)
+	}
+
+	return r
+}
+
+// assignClaims assigns each of n messages to at most one child such that as
+// many children as possible are assigned a message, where claims contains the
+// indices of the messages that each child may claim.
+//
+// It returns the index of the child that each message is assigned to, or -1
+// if the message is not assigned to any child.
+func assignClaims(claims [][]int, n int) []int {
+	owners := make([]int, n)
+	for m := range owners {
+		owners[m] = -1
+	}
+
+	// assign attempts to assign a message to the child at index i, reassigning
+	// the messages already assigned to other children if necessary.
+	var assign func(i int, visited []bool) bool
+	assign = func(i int, visited []bool) bool {
+		for _, m := range claims[i] {
+			if visited[m] {
+				continue
+			}
+
+			visited[m] = true
+
+			if owners[m] == -1 || assign(owners[m], visited) {
+				owners[m] = i
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := range claims {
+		assign(i, make([]bool, n))
+	}
+
+	return owners
+}
+
+func (p *produceExactlyPredicate) Report(ctx ReportGenerationContext) *Report {
+	ok := p.Ok()
+
+	rep := &Report{
+		TreeOk:   ctx.TreeOk,
+		Ok:       ok,
+		Criteria: "produce exactly these messages",
+	}
+
+	if !ok {
+		var outcomes []string
+
+		if n := p.failed(); n != 0 {
+			outcomes = append(
+				outcomes,
+				fmt.Sprintf("%d of the expectations failed", n),
+			)
+		}
+
+		if n := len(p.unclaimed); n == 1 {
+			outcomes = append(outcomes, "1 message was not expected")
+		} else if n != 0 {
+			outcomes = append(
+				outcomes,
+				fmt.Sprintf("%d messages were not expected", n),
+			)
+		}
+
+		rep.Outcome = strings.Join(outcomes, ", ")
+	}
+
+	for i, r := range p.results {
+		rep.Append(p.childReport(ctx, i, r))
+	}
+
+	if ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if len(p.unclaimed) == 0 {
+		return rep
+	}
+
+	list := rep.Section(unclaimedMessagesSection)
+	for _, env := range p.unclaimed {
+		list.AppendListItem("%s", describeEnvelope(env))
+	}
+
+	suggestVerifyingHandlers(rep.Section(suggestionsSection), p.unclaimed)
+
+	return rep
+}
+
+// childReport returns the report for the child at index i, where r is the
+// predicate that describes its outcome.
+func (p *produceExactlyPredicate) childReport(
+	ctx ReportGenerationContext,
+	i int,
+	r Predicate,
+) *Report {
+	rep := r.Report(ctx)
+
+	if rep.Ok || ctx.TreeOk || ctx.IsInverted || !p.contested[i] {
+		return rep
+	}
+
+	// The child was not offered the messages that it could have claimed, so
+	// its own explanation and suggestions are not relevant.
+	rep.Sections = nil
+	rep.Explanation = "every matching message was claimed by another expectation"
+
+	return rep
+}
@@ -0,0 +1,229 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToProduceExactly()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "ae6b2f30-7c8e-4a03-9fb0-4e5f60718293")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "bf7c3041-8d9f-4b14-a0c1-5f60718293a4")
+						c.Routes(
+							dogma.HandlesCommand[CommandStub[TypeA]](),
+							dogma.HandlesCommand[CommandStub[TypeB]](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+							dogma.RecordsEvent[EventStub[TypeB]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						switch m.(type) {
+						case CommandStub[TypeA]:
+							s.RecordEvent(EventA1)
+							s.RecordEvent(EventA1)
+						case CommandStub[TypeB]:
+							s.RecordEvent(EventB1)
+						}
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "c08d4152-9ea0-4c25-b1d2-60718293a4b5")
+						c.Routes(
+							dogma.HandlesEvent[EventStub[TypeB]](),
+							dogma.ExecutesCommand[CommandStub[TypeA]](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						_ dogma.ProcessRoot,
+						s dogma.ProcessEventScope,
+						_ dogma.Event,
+					) error {
+						s.ExecuteCommand(CommandA1)
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(ExecuteCommand(CommandB1), e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"it passes when every message is claimed and every expectation is met",
+			ToProduceExactly(
+				ToRecordEvent(EventB1),
+				ToExecuteCommand(CommandA1),
+				ToRecordEvent(EventA1),
+				ToRecordEvent(EventA1),
+			),
+			expectPass,
+			expectReport(
+				`✓ produce exactly these messages`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+				`    ✓ execute a specific 'stubs.CommandStub[TypeA]' command`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+			),
+		),
+		g.Entry(
+			"it fails when a message is not claimed",
+			ToProduceExactly(
+				ToRecordEvent(EventB1),
+				ToRecordEventType[EventStub[TypeA]](),
+			),
+			expectFail,
+			expectReport(
+				`✗ produce exactly these messages (2 messages were not expected)`,
+				``,
+				`  | UNCLAIMED MESSAGES`,
+				`  |     • stubs.CommandStub[TypeA] command executed by the '<process>' process message handler: command(stubs.TypeA:A1, valid)`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+				`    ✓ record any 'stubs.EventStub[TypeA]' event`,
+			),
+		),
+		g.Entry(
+			"it fails when an expectation is not met",
+			ToProduceExactly(
+				ToRecordEvent(EventB1),
+				ToExecuteCommand(CommandA1),
+				ToRecordEvent(EventA1),
+				ToRecordEvent(EventA1),
+				ToRecordEvent(EventA1),
+			),
+			expectFail,
+			expectReport(
+				`✗ produce exactly these messages (1 of the expectations failed)`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+				`    ✓ execute a specific 'stubs.CommandStub[TypeA]' command`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✗ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    `,
+				`      | EXPLANATION`,
+				`      |     every matching message was claimed by another expectation`,
+			),
+		),
+		g.Entry(
+			"it assigns the messages such that as many expectations as possible are met",
+			ToProduceExactly(
+				ToRecordEventMatching(func(dogma.Event) error { return nil }),
+				ToRecordEvent(EventB1),
+				ToExecuteCommand(CommandA1),
+				ToRecordEvent(EventA1),
+			),
+			expectPass,
+			expectReport(
+				`✓ produce exactly these messages`,
+				`    ✓ record an event that matches the predicate near expectation.produceexactly_test.go:157`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+				`    ✓ execute a specific 'stubs.CommandStub[TypeA]' command`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+			),
+		),
+		g.Entry(
+			"it fails when an expectation does not match any of the messages",
+			ToProduceExactly(
+				ToRecordEvent(EventB1),
+				ToExecuteCommand(CommandA1),
+				ToRecordEvent(EventA1),
+				ToRecordEvent(EventA2),
+			),
+			expectFail,
+			expectReport(
+				`✗ produce exactly these messages (1 of the expectations failed, 1 message was not expected)`,
+				``,
+				`  | UNCLAIMED MESSAGES`,
+				`  |     • stubs.EventStub[TypeA] event recorded by the '<aggregate>' aggregate message handler: event(stubs.TypeA:A1, valid)`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`    ✓ record a specific 'stubs.EventStub[TypeB]' event`,
+				`    ✓ execute a specific 'stubs.CommandStub[TypeA]' command`,
+				`    ✓ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    ✗ record a specific 'stubs.EventStub[TypeA]' event`,
+				`    `,
+				`      | EXPLANATION`,
+				`      |     a similar event was recorded by the '<aggregate>' aggregate message handler`,
+				`      | `,
+				`      | SUGGESTIONS`,
+				`      |     • check the content of the message`,
+				`      | `,
+				`      | MESSAGE DIFF`,
+				`      |     stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]{`,
+				`      |         Content:         "A[-2-]{+1+}"`,
+				`      |         ValidationError: ""`,
+				`      |     }`,
+			),
+		),
+	)
+
+	g.It("fails the test if a child is met before the action is performed", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			ExecuteCommand(CommandB1),
+			ToProduceExactly(
+				ToRecordEvent(EventB1),
+				Not(ToRecordEvent(EventA2)),
+			),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"ToProduceExactly(): expectation 2 (not to record a specific 'stubs.EventStub[TypeA]' event) is met before the action is performed, so it can not claim a message",
+		))
+	})
+
+	g.It("panics if no children are provided", func() {
+		gm.Expect(func() {
+			ToProduceExactly()
+		}).To(gm.PanicWith("ToProduceExactly(): at least one child expectation must be provided"))
+	})
+})
@@ -0,0 +1,154 @@
+package testkit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/testkit/envelope"
+)
+
+// ScheduleOption is an option that constrains the time at which a timeout
+// message is expected to be scheduled.
+//
+// It is accepted by [ToScheduleTimeout], [ToScheduleTimeoutType] and
+// [ToScheduleTimeoutMatching]. If multiple options are provided the timeout
+// must satisfy all of them.
+type ScheduleOption interface {
+	applyScheduleOption(*scheduleConstraints)
+}
+
+// ScheduledFor returns a ScheduleOption that requires a timeout to be scheduled
+// for exactly t.
+func ScheduledFor(t time.Time) ScheduleOption {
+	return scheduleConstraint{
+		desc: fmt.Sprintf("for %s", formatScheduleTime(t)),
+		pred: func(s time.Time) bool {
+			return s.Equal(t)
+		},
+	}
+}
+
+// ScheduledBefore returns a ScheduleOption that requires a timeout to be
+// scheduled for some time before t.
+func ScheduledBefore(t time.Time) ScheduleOption {
+	return scheduleConstraint{
+		desc: fmt.Sprintf("before %s", formatScheduleTime(t)),
+		pred: func(s time.Time) bool {
+			return s.Before(t)
+		},
+	}
+}
+
+// ScheduledAfter returns a ScheduleOption that requires a timeout to be
+// scheduled for some time after t.
+func ScheduledAfter(t time.Time) ScheduleOption {
+	return scheduleConstraint{
+		desc: fmt.Sprintf("after %s", formatScheduleTime(t)),
+		pred: func(s time.Time) bool {
+			return s.After(t)
+		},
+	}
+}
+
+// ScheduledWithin returns a ScheduleOption that requires a timeout to be
+// scheduled for some time within the window that begins at start and ends at
+// end, inclusive.
+func ScheduledWithin(start, end time.Time) ScheduleOption {
+	if end.Before(start) {
+		panic(fmt.Sprintf(
+			"ScheduledWithin(%s, %s): end must not be before start",
+			formatScheduleTime(start),
+			formatScheduleTime(end),
+		))
+	}
+
+	return scheduleConstraint{
+		desc: fmt.Sprintf(
+			"between %s and %s",
+			formatScheduleTime(start),
+			formatScheduleTime(end),
+		),
+		pred: func(s time.Time) bool {
+			return !s.Before(start) && !s.After(end)
+		},
+	}
+}
+
+// scheduleConstraint is a constraint on the time at which a timeout message is
+// scheduled.
+type scheduleConstraint struct {
+	desc string
+	pred func(time.Time) bool
+}
+
+func (c scheduleConstraint) applyScheduleOption(cs *scheduleConstraints) {
+	*cs = append(*cs, c)
+}
+
+// scheduleConstraints is a set of constraints that must all be satisfied by a
+// timeout message.
+//
+// An empty set of constraints is satisfied by any timeout, and by messages of
+// any other kind.
+type scheduleConstraints []scheduleConstraint
+
+// newScheduleConstraints returns the constraints described by the given
+// options.
+func newScheduleConstraints(options []ScheduleOption) scheduleConstraints {
+	var cs scheduleConstraints
+
+	for _, opt := range options {
+		if opt == nil {
+			panic("schedule option must not be nil")
+		}
+
+		opt.applyScheduleOption(&cs)
+	}
+
+	return cs
+}
+
+// IsSatisfiedBy returns true if env satisfies all of the constraints.
+func (cs scheduleConstraints) IsSatisfiedBy(env *envelope.Envelope) bool {
+	for _, c := range cs {
+		if !c.pred(env.ScheduledFor) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns a description of the constraints, suitable for appending to
+// a report's criteria.
+//
+// It returns an empty string if there are no constraints.
+func (cs scheduleConstraints) String() string {
+	if len(cs) == 0 {
+		return ""
+	}
+
+	var desc []string
+	for _, c := range cs {
+		desc = append(desc, c.desc)
+	}
+
+	return " " + strings.Join(desc, " and ")
+}
+
+// Violation returns a human-readable description of the way in which env fails
+// to meet the constraints.
+func (cs scheduleConstraints) Violation(env *envelope.Envelope) string {
+	return fmt.Sprintf(
+		"scheduled for %s, expected%s",
+		formatScheduleTime(env.ScheduledFor),
+		cs,
+	)
+}
+
+// formatScheduleTime returns the representation of t used within test reports
+// that refer to the time at which a timeout is scheduled.
+func formatScheduleTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,225 @@
+package testkit
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/location"
+)
+
+const (
+	// stateErrorSection is the heading for the section of the test report that
+	// shows the error returned by the function used with ToHaveAggregateState()
+	// or ToHaveProcessState().
+	stateErrorSection = "Predicate Error"
+
+	// rootSection is the heading for the section of the test report that shows
+	// the aggregate or process root that was inspected by an expectation.
+	rootSection = "Root"
+)
+
+// ToHaveAggregateState returns an expectation that passes if the root of the
+// aggregate instance with the given ID satisfies the given predicate function
+// once the action has completed.
+//
+// pred is the predicate function. It is called with the instance's root, as
+// rebuilt from its history. It must return nil for the expectation to pass. The
+// expectation fails if the instance does not exist, or if its root is not of
+// type R.
+func ToHaveAggregateState[R dogma.AggregateRoot](
+	handler, id string,
+	pred func(R) error,
+) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToHaveAggregateState(%#v, %#v, ...): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("ToHaveAggregateState(%#v, %#v, ...): instance ID must not be empty", handler, id))
+	}
+
+	if pred == nil {
+		panic(fmt.Sprintf("ToHaveAggregateState(%#v, %#v, <nil>): function must not be nil", handler, id))
+	}
+
+	return &stateExpectation[R]{
+		handler:     handler,
+		instanceID:  id,
+		handlerType: configkit.AggregateHandlerType,
+		pred:        pred,
+	}
+}
+
+// ToHaveProcessState returns an expectation that passes if the root of the
+// process instance with the given ID satisfies the given predicate function
+// once the action has completed.
+//
+// pred is the predicate function. It is called with a copy of the instance's
+// root. It must return nil for the expectation to pass. The expectation fails
+// if the instance does not exist, or if its root is not of type R.
+func ToHaveProcessState[R dogma.ProcessRoot](
+	handler, id string,
+	pred func(R) error,
+) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToHaveProcessState(%#v, %#v, ...): handler name must not be empty", handler, id))
+	}
+
+	if id == "" {
+		panic(fmt.Sprintf("ToHaveProcessState(%#v, %#v, ...): instance ID must not be empty", handler, id))
+	}
+
+	if pred == nil {
+		panic(fmt.Sprintf("ToHaveProcessState(%#v, %#v, <nil>): function must not be nil", handler, id))
+	}
+
+	return &stateExpectation[R]{
+		handler:     handler,
+		instanceID:  id,
+		handlerType: configkit.ProcessHandlerType,
+		pred:        pred,
+	}
+}
+
+// stateExpectation is an Expectation that checks the state of an aggregate or
+// process instance once the action has completed.
+//
+// It is the implementation used by ToHaveAggregateState() and
+// ToHaveProcessState().
+type stateExpectation[R any] struct {
+	handler     string
+	instanceID  string
+	handlerType configkit.HandlerType
+	pred        func(R) error
+}
+
+func (e *stateExpectation[R]) Caption() string {
+	return fmt.Sprintf(
+		"to have the expected state in the '%s' instance of the '%s' %s",
+		e.instanceID,
+		e.handler,
+		e.handlerType,
+	)
+}
+
+func (e *stateExpectation[R]) Predicate(s PredicateScope) (Predicate, error) {
+	if err := guardAgainstExpectationOnUnknownHandler(
+		s,
+		e.handler,
+		e.handlerType,
+	); err != nil {
+		return nil, err
+	}
+
+	if s.engine == nil {
+		return nil, errors.New("state expectations can only be used with Test.Expect()")
+	}
+
+	p := &statePredicate[R]{
+		expectation: e,
+	}
+
+	p.load = func() (any, bool, error) {
+		if e.handlerType == configkit.AggregateHandlerType {
+			return s.engine.AggregateRoot(s.ctx, e.handler, e.instanceID)
+		}
+		return s.engine.ProcessRoot(s.ctx, e.handler, e.instanceID)
+	}
+
+	return p, nil
+}
+
+// statePredicate is the Predicate implementation for stateExpectation.
+type statePredicate[R any] struct {
+	expectation *stateExpectation[R]
+	load        func() (any, bool, error)
+
+	ok       bool
+	exists   bool
+	root     any
+	loadErr  error
+	predErr  error
+	typeDiff bool
+}
+
+func (p *statePredicate[R]) Notify(fact.Fact) {
+}
+
+func (p *statePredicate[R]) Ok() bool {
+	return p.ok
+}
+
+func (p *statePredicate[R]) Done() {
+	p.root, p.exists, p.loadErr = p.load()
+	if p.loadErr != nil || !p.exists {
+		return
+	}
+
+	r, ok := p.root.(R)
+	if !ok {
+		p.typeDiff = true
+		return
+	}
+
+	p.predErr = p.expectation.pred(r)
+	p.ok = p.predErr == nil
+}
+
+func (p *statePredicate[R]) Report(ctx ReportGenerationContext) *Report {
+	e := p.expectation
+
+	rep := &Report{
+		TreeOk: ctx.TreeOk,
+		Ok:     p.ok,
+		Criteria: fmt.Sprintf(
+			"cause the '%s' instance of the '%s' %s to have a root that satisfies the predicate near %s",
+			e.instanceID,
+			e.handler,
+			e.handlerType,
+			location.OfFunc(e.pred),
+		),
+	}
+
+	if p.ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	s := rep.Section(suggestionsSection)
+
+	if p.loadErr != nil {
+		rep.Explanation = fmt.Sprintf("the state could not be inspected: %s", p.loadErr)
+		return rep
+	}
+
+	if !p.exists {
+		rep.Explanation = "the instance does not exist"
+		s.AppendListItem("verify that the correct instance ID is being tested")
+		s.AppendListItem("check the application's routing configuration")
+		return rep
+	}
+
+	if p.typeDiff {
+		rep.Explanation = fmt.Sprintf(
+			"the root is a %s, not a %s",
+			reflect.TypeOf(p.root),
+			reflect.TypeFor[R](),
+		)
+		s.AppendListItem("check the type parameter of the predicate function")
+		return rep
+	}
+
+	rep.Explanation = "the root does not satisfy the predicate"
+	rep.Section(stateErrorSection).Append("%s", p.predErr)
+	rep.Section(rootSection).Append("%s", ctx.renderValue(p.root))
+	s.AppendListItem(
+		"verify the logic within the '%s' %s message handler",
+		e.handler,
+		e.handlerType,
+	)
+
+	return rep
+}
@@ -0,0 +1,302 @@
+package testkit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+// otherAggregateRoot is an aggregate root of a type that is not used by any
+// handler.
+type otherAggregateRoot struct {
+	AggregateRootStub
+}
+
+// hasValue returns an error if r does not have the value v.
+func hasValue(r *ProcessRootStub, v any) error {
+	if r.Value != v {
+		return fmt.Errorf("unexpected value: %v", r.Value)
+	}
+	return nil
+}
+
+var _ = g.Describe("state expectations", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		CommandForAggregate = CommandStub[TypeA]
+		EventForProcess     = EventStub[TypeA]
+		CommandForProcess   = CommandStub[TypeP]
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "7f3e9b1a-2c4d-4e6f-8a0b-1c3d5e7f9a2b")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "8a4f0c2b-3d5e-4f70-9b1c-2d4e6f8a0b3c")
+						c.Routes(
+							dogma.HandlesCommand[CommandForAggregate](),
+							dogma.RecordsEvent[EventForProcess](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return "<instance>"
+					},
+					HandleCommandFunc: func(
+						_ dogma.AggregateRoot,
+						s dogma.AggregateCommandScope,
+						m dogma.Command,
+					) {
+						s.RecordEvent(EventForProcess{
+							Content: m.(CommandForAggregate).Content,
+						})
+					},
+				})
+
+				c.RegisterProcess(&ProcessMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("<process>", "9b5a1d3c-4e6f-4a81-8c2d-3e5f7a9b1c4d")
+						c.Routes(
+							dogma.HandlesEvent[EventForProcess](),
+							dogma.ExecutesCommand[CommandForProcess](),
+						)
+					},
+					RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+						return "<instance>", true, nil
+					},
+					HandleEventFunc: func(
+						_ context.Context,
+						r dogma.ProcessRoot,
+						_ dogma.ProcessEventScope,
+						m dogma.Event,
+					) error {
+						r.(*ProcessRootStub).Value = m.(EventForProcess).Content
+						return nil
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"aggregate root satisfies the predicate",
+			ExecuteCommand(CommandForAggregate{Content: "<content>"}),
+			ToHaveAggregateState(
+				"<aggregate>",
+				"<instance>",
+				func(r *AggregateRootStub) error { return nil },
+			),
+			expectPass,
+			expectReport(
+				`✓ cause the '<instance>' instance of the '<aggregate>' aggregate to have a root that satisfies the predicate near expectation.state_test.go:117`,
+			),
+		),
+		g.Entry(
+			"aggregate root does not satisfy the predicate",
+			ExecuteCommand(CommandForAggregate{Content: "<content>"}),
+			ToHaveAggregateState(
+				"<aggregate>",
+				"<instance>",
+				func(r *AggregateRootStub) error { return errors.New("<error>") },
+			),
+			expectFail,
+			expectReport(
+				`✗ cause the '<instance>' instance of the '<aggregate>' aggregate to have a root that satisfies the predicate near expectation.state_test.go:130`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the root does not satisfy the predicate`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+				`  | `,
+				`  | PREDICATE ERROR`,
+				`  |     <error>`,
+				`  | `,
+				`  | ROOT`,
+				`  |     *stubs.AggregateRootStub{`,
+				`  |         AppliedEvents:  {`,
+				`  |             stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]{`,
+				`  |                 Content:         "<content>"`,
+				`  |                 ValidationError: ""`,
+				`  |             }`,
+				`  |         }`,
+				`  |         ApplyEventFunc: nil`,
+				`  |     }`,
+			),
+		),
+		g.Entry(
+			"aggregate instance does not exist",
+			noop,
+			ToHaveAggregateState(
+				"<aggregate>",
+				"<instance>",
+				func(r *AggregateRootStub) error { return nil },
+			),
+			expectFail,
+			expectReport(
+				`✗ cause the '<instance>' instance of the '<aggregate>' aggregate to have a root that satisfies the predicate near expectation.state_test.go:163`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the instance does not exist`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify that the correct instance ID is being tested`,
+				`  |     • check the application's routing configuration`,
+			),
+		),
+		g.Entry(
+			"aggregate root is of a different type",
+			ExecuteCommand(CommandForAggregate{Content: "<content>"}),
+			ToHaveAggregateState(
+				"<aggregate>",
+				"<instance>",
+				func(r *otherAggregateRoot) error { return nil },
+			),
+			expectFail,
+			expectReport(
+				`✗ cause the '<instance>' instance of the '<aggregate>' aggregate to have a root that satisfies the predicate near expectation.state_test.go:183`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the root is a *stubs.AggregateRootStub, not a *testkit_test.otherAggregateRoot`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the type parameter of the predicate function`,
+			),
+		),
+		g.Entry(
+			"process root satisfies the predicate",
+			RecordEvent(EventForProcess{Content: "<content>"}),
+			ToHaveProcessState(
+				"<process>",
+				"<instance>",
+				func(r *ProcessRootStub) error { return hasValue(r, TypeA("<content>")) },
+			),
+			expectPass,
+			expectReport(
+				`✓ cause the '<instance>' instance of the '<process>' process to have a root that satisfies the predicate near expectation.state_test.go:202`,
+			),
+		),
+		g.Entry(
+			"process root does not satisfy the predicate",
+			RecordEvent(EventForProcess{Content: "<content>"}),
+			ToHaveProcessState(
+				"<process>",
+				"<instance>",
+				func(r *ProcessRootStub) error { return errors.New("<error>") },
+			),
+			expectFail,
+			expectReport(
+				`✗ cause the '<instance>' instance of the '<process>' process to have a root that satisfies the predicate near expectation.state_test.go:215`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the root does not satisfy the predicate`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<process>' process message handler`,
+				`  | `,
+				`  | PREDICATE ERROR`,
+				`  |     <error>`,
+				`  | `,
+				`  | ROOT`,
+				`  |     *stubs.ProcessRootStub{`,
+				`  |         Value: stubs.TypeA("<content>")`,
+				`  |     }`,
+			),
+		),
+		g.Entry(
+			"process root satisfies the predicate unexpectedly",
+			RecordEvent(EventForProcess{Content: "<content>"}),
+			Not(ToHaveProcessState(
+				"<process>",
+				"<instance>",
+				func(r *ProcessRootStub) error { return nil },
+			)),
+			expectFail,
+			expectReport(
+				`✗ do not cause the '<instance>' instance of the '<process>' process to have a root that satisfies the predicate near expectation.state_test.go:242`,
+			),
+		),
+	)
+
+	g.It("includes annotations in the rendered root", func() {
+		Begin(testingT, app).
+			Annotate(TypeA("<content>"), "the annotated value").
+			Expect(
+				RecordEvent(EventForProcess{Content: "<content>"}),
+				ToHaveProcessState(
+					"<process>",
+					"<instance>",
+					func(r *ProcessRootStub) error { return errors.New("<error>") },
+				),
+			)
+
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			gm.ContainSubstring(`Value: stubs.TypeA("<content>") <<the annotated value>>`),
+		))
+	})
+
+	g.It("fails the test if the handler is of a different type", func() {
+		Begin(testingT, app).
+			Expect(
+				noop,
+				ToHaveAggregateState(
+					"<process>",
+					"<instance>",
+					func(r *AggregateRootStub) error { return nil },
+				),
+			)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"'<process>' is a process message handler, not an aggregate message handler",
+		))
+	})
+
+	g.It("panics if the handler name is empty", func() {
+		gm.Expect(func() {
+			ToHaveAggregateState("", "<instance>", func(*AggregateRootStub) error { return nil })
+		}).To(gm.PanicWith(`ToHaveAggregateState("", "<instance>", ...): handler name must not be empty`))
+	})
+
+	g.It("panics if the instance ID is empty", func() {
+		gm.Expect(func() {
+			ToHaveProcessState("<process>", "", func(*ProcessRootStub) error { return nil })
+		}).To(gm.PanicWith(`ToHaveProcessState("<process>", "", ...): instance ID must not be empty`))
+	})
+
+	g.It("panics if the predicate function is nil", func() {
+		gm.Expect(func() {
+			ToHaveAggregateState[*AggregateRootStub]("<aggregate>", "<instance>", nil)
+		}).To(gm.PanicWith(`ToHaveAggregateState("<aggregate>", "<instance>", <nil>): function must not be nil`))
+	})
+})
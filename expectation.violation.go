@@ -0,0 +1,140 @@
+package testkit
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/testkit/fact"
+)
+
+const (
+	// unexpectedBehaviorSection is the heading for the section of the test
+	// report that lists the occurrences of unexpected handler behavior.
+	unexpectedBehaviorSection = "Unexpected Behavior"
+)
+
+// ToViolateSpecification returns an expectation that passes if the message
+// handler named handler behaves in a way that violates the Dogma
+// specification, such as returning a nil root from New(), routing a message to
+// an empty instance ID, or producing a message of a type that it does not
+// declare in its routes.
+//
+// Ordinarily such behavior causes the engine to panic. When this expectation is
+// used the engine instead recovers from the panic and the behavior is reported
+// on by the expectation.
+func ToViolateSpecification(handler string) Expectation {
+	if handler == "" {
+		panic(fmt.Sprintf("ToViolateSpecification(%#v): handler name must not be empty", handler))
+	}
+
+	return &violationExpectation{
+		handler: handler,
+	}
+}
+
+// violationExpectation is an Expectation that checks that a handler violates
+// the Dogma specification.
+//
+// It is the implementation used by ToViolateSpecification().
+type violationExpectation struct {
+	handler string
+}
+
+func (e *violationExpectation) Caption() string {
+	return fmt.Sprintf("to violate the specification within the '%s' handler", e.handler)
+}
+
+func (e *violationExpectation) Predicate(s PredicateScope) (Predicate, error) {
+	// TODO: These checks should result in information being added to the
+	// report, not just returning an error.
+	//
+	// See https://github.com/dogmatiq/testkit/issues/162
+	h, err := lookupHandler(s.App, e.handler)
+	if err != nil {
+		return nil, err
+	}
+
+	s.expectUnexpectedBehavior()
+
+	return &violationPredicate{
+		handler: h,
+		tracker: instanceTracker{
+			handler: e.handler,
+		},
+	}, nil
+}
+
+// violationPredicate is the Predicate implementation for violationExpectation.
+type violationPredicate struct {
+	handler    configkit.RichHandler
+	tracker    instanceTracker
+	ok         bool
+	violations []fact.UnexpectedBehaviorRecovered
+}
+
+func (p *violationPredicate) Notify(f fact.Fact) {
+	p.tracker.Notify(f)
+
+	if x, ok := f.(fact.UnexpectedBehaviorRecovered); ok {
+		if x.Handler.Identity() == p.handler.Identity() {
+			p.ok = true
+		}
+
+		p.violations = append(p.violations, x)
+	}
+}
+
+func (p *violationPredicate) Ok() bool {
+	return p.ok
+}
+
+func (p *violationPredicate) Done() {
+}
+
+func (p *violationPredicate) Report(ctx ReportGenerationContext) *Report {
+	rep := &Report{
+		TreeOk: ctx.TreeOk,
+		Ok:     p.ok,
+		Criteria: fmt.Sprintf(
+			"cause the '%s' %s message handler to violate the specification",
+			p.handler.Identity().Name,
+			p.handler.HandlerType(),
+		),
+	}
+
+	if p.ok || ctx.TreeOk || ctx.IsInverted {
+		return rep
+	}
+
+	if p.tracker.reportNotEngaged(rep, p.handler.HandlerType()) {
+		return rep
+	}
+
+	if len(p.violations) == 0 {
+		rep.Explanation = "the handler behaved as expected"
+		rep.Section(suggestionsSection).AppendListItem(
+			"verify the logic within the '%s' %s message handler",
+			p.handler.Identity().Name,
+			p.handler.HandlerType(),
+		)
+		return rep
+	}
+
+	rep.Explanation = "a different handler violated the specification"
+
+	u := rep.Section(unexpectedBehaviorSection)
+	for _, v := range p.violations {
+		u.AppendListItem(
+			"the '%s' %s message handler behaved unexpectedly in %T.%s(): %s",
+			v.Handler.Identity().Name,
+			v.Handler.HandlerType(),
+			v.Implementation,
+			v.Method,
+			v.Description,
+		)
+	}
+
+	rep.Section(suggestionsSection).AppendListItem("verify that the correct handler is being tested")
+
+	return rep
+}
@@ -0,0 +1,194 @@
+package testkit_test
+
+import (
+	"context"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/internal/testingmock"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("func ToViolateSpecification()", func() {
+	var (
+		testingT *testingmock.T
+		app      dogma.Application
+	)
+
+	type (
+		CommandThatViolates    = CommandStub[TypeV]
+		CommandThatComplies    = CommandStub[TypeC]
+		CommandForOtherHandler = CommandStub[TypeO]
+	)
+
+	g.BeforeEach(func() {
+		testingT = &testingmock.T{
+			FailSilently: true,
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "4d5e6f70-8192-4a3b-9c4d-5e6f708192a3")
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<aggregate>", "6f708192-a3b4-4c5d-8e6f-708192a3b4c5")
+						c.Routes(
+							dogma.HandlesCommand[CommandThatViolates](),
+							dogma.HandlesCommand[CommandThatComplies](),
+							dogma.RecordsEvent[EventStub[TypeA]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(m dogma.Command) string {
+						if _, ok := m.(CommandThatViolates); ok {
+							return ""
+						}
+						return "<instance>"
+					},
+				})
+
+				c.RegisterAggregate(&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("<other>", "8192a3b4-c5d6-4e7f-8091-a2b3c4d5e6f7")
+						c.Routes(
+							dogma.HandlesCommand[CommandForOtherHandler](),
+							dogma.RecordsEvent[EventStub[TypeB]](),
+						)
+					},
+					RouteCommandToInstanceFunc: func(dogma.Command) string {
+						return ""
+					},
+				})
+			},
+		}
+	})
+
+	g.DescribeTable(
+		"expectation behavior",
+		func(
+			a Action,
+			e Expectation,
+			ok bool,
+			rm reportMatcher,
+		) {
+			test := Begin(testingT, app)
+			test.Expect(a, e)
+			rm(testingT)
+			gm.Expect(testingT.Failed()).To(gm.Equal(!ok))
+		},
+		g.Entry(
+			"specification violated as expected",
+			ExecuteCommand(CommandThatViolates{}),
+			ToViolateSpecification("<aggregate>"),
+			expectPass,
+			expectReport(
+				`✓ cause the '<aggregate>' aggregate message handler to violate the specification`,
+			),
+		),
+		g.Entry(
+			"specification not violated",
+			ExecuteCommand(CommandThatComplies{}),
+			ToViolateSpecification("<aggregate>"),
+			expectFail,
+			expectReport(
+				`✗ cause the '<aggregate>' aggregate message handler to violate the specification`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the handler behaved as expected`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • verify the logic within the '<aggregate>' aggregate message handler`,
+			),
+		),
+		g.Entry(
+			"specification violated by a different handler",
+			ExecuteCommand(CommandForOtherHandler{}),
+			ToViolateSpecification("<aggregate>"),
+			expectFail,
+			expectReport(
+				`✗ cause the '<aggregate>' aggregate message handler to violate the specification`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the '<aggregate>' aggregate message handler was not engaged`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the application's routing configuration`,
+			),
+		),
+		g.Entry(
+			"specification not violated by a different handler",
+			ExecuteCommand(CommandThatComplies{}),
+			ToViolateSpecification("<other>"),
+			expectFail,
+			expectReport(
+				`✗ cause the '<other>' aggregate message handler to violate the specification`,
+				``,
+				`  | EXPLANATION`,
+				`  |     the '<other>' aggregate message handler was not engaged`,
+				`  | `,
+				`  | SUGGESTIONS`,
+				`  |     • check the application's routing configuration`,
+			),
+		),
+	)
+
+	g.It("lists the unexpected behavior of other handlers", func() {
+		test := Begin(testingT, app)
+		executor := test.CommandExecutor()
+
+		test.Expect(
+			Call(func() {
+				executor.ExecuteCommand(context.Background(), CommandThatComplies{})
+				executor.ExecuteCommand(context.Background(), CommandForOtherHandler{})
+			}),
+			ToViolateSpecification("<aggregate>"),
+		)
+
+		expectReport(
+			`✗ cause the '<aggregate>' aggregate message handler to violate the specification`,
+			``,
+			`  | EXPLANATION`,
+			`  |     a different handler violated the specification`,
+			`  | `,
+			`  | UNEXPECTED BEHAVIOR`,
+			`  |     • the '<other>' aggregate message handler behaved unexpectedly in *stubs.AggregateMessageHandlerStub.RouteCommandToInstance(): routed a command of type stubs.CommandStub[TypeO] to an empty ID`,
+			`  | `,
+			`  | SUGGESTIONS`,
+			`  |     • verify that the correct handler is being tested`,
+		)(testingT)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+	})
+
+	g.It("does not recover from unexpected behavior when the expectation is not used", func() {
+		test := Begin(testingT, app)
+
+		gm.Expect(func() {
+			test.Expect(
+				ExecuteCommand(CommandThatViolates{}),
+				pass,
+			)
+		}).To(gm.Panic())
+	})
+
+	g.It("fails the test if the handler does not exist", func() {
+		test := Begin(testingT, app)
+		test.Expect(
+			noop,
+			ToViolateSpecification("<unknown>"),
+		)
+
+		gm.Expect(testingT.Failed()).To(gm.BeTrue())
+		gm.Expect(testingT.Logs).To(gm.ContainElement(
+			"the '<app>' application does not have a handler named '<unknown>'",
+		))
+	})
+
+	g.It("panics if the handler name is empty", func() {
+		gm.Expect(func() {
+			ToViolateSpecification("")
+		}).To(gm.PanicWith(`ToViolateSpecification(""): handler name must not be empty`))
+	})
+})
@@ -71,3 +71,13 @@ type MessageLoggedByAggregate struct {
 	LogFormat    string
 	LogArguments []any
 }
+
+// AggregateHistorySeeded indicates that events were added directly to the
+// history of an aggregate instance, without being recorded by the aggregate
+// message handler.
+type AggregateHistorySeeded struct {
+	Handler        configkit.RichAggregate
+	InstanceID     string
+	Root           dogma.AggregateRoot
+	EventEnvelopes []*envelope.Envelope
+}
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
 	"github.com/dogmatiq/testkit/envelope"
+	"github.com/dogmatiq/testkit/location"
 )
 
 // DispatchCycleBegun indicates that Engine.Dispatch() has been called with a
@@ -36,6 +38,34 @@ type DispatchCompleted struct {
 	Error    error
 }
 
+// DispatchLimitExceeded indicates that the engine has stopped dispatching
+// messages because one of the limits set via engine.WithDispatchLimit() or
+// engine.WithCausationDepthLimit() has been exceeded.
+//
+// It is typically caused by handlers that repeatedly produce messages that
+// cause each other, such as a process and an aggregate that "ping-pong"
+// commands and events indefinitely.
+type DispatchLimitExceeded struct {
+	// Envelope is the message that would have exceeded the limit, had it been
+	// dispatched.
+	Envelope *envelope.Envelope
+
+	// Dispatched is the number of messages that were dispatched before the
+	// engine stopped.
+	Dispatched int
+
+	// Depth is the length of the causation chain of Envelope, including the
+	// message itself.
+	Depth int
+
+	// Cycle is the sequence of message types that repeats within the causation
+	// chain of Envelope, if any. The first and last elements are the same.
+	Cycle []message.Type
+
+	// Error is the error returned by the engine.
+	Error error
+}
+
 // HandlingBegun indicates that a message is about to be handled by a specific
 // handler.
 type HandlingBegun struct {
@@ -59,3 +89,30 @@ type HandlingSkipped struct {
 	Envelope *envelope.Envelope
 	Reason   HandlerSkipReason
 }
+
+// UnexpectedBehaviorRecovered indicates that a handler behaved in a way that
+// the engine did not expect, and that the engine recovered from the resulting
+// panic instead of propagating it to the caller.
+//
+// Often this means the handler has violated the Dogma specification.
+type UnexpectedBehaviorRecovered struct {
+	Handler  configkit.RichHandler
+	Envelope *envelope.Envelope
+
+	// Interface is the name of the interface containing the method with the
+	// unexpected behavior.
+	Interface string
+
+	// Method is the name of the method that behaved unexpectedly.
+	Method string
+
+	// Implementation is the value that implements the nominated interface.
+	Implementation any
+
+	// Description is a human-readable description of the behavior.
+	Description string
+
+	// Location is the engine's best attempt at pinpointing the location of the
+	// unexpected behavior.
+	Location location.Location
+}
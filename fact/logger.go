@@ -31,10 +31,14 @@ func (l *Logger) Notify(f Fact) {
 		l.dispatchCycleBegun(x)
 	case DispatchBegun:
 		l.dispatchBegun(x)
+	case DispatchLimitExceeded:
+		l.dispatchLimitExceeded(x)
 	case HandlingCompleted:
 		l.handlingCompleted(x)
 	case HandlingSkipped:
 		l.handlingSkipped(x)
+	case UnexpectedBehaviorRecovered:
+		l.unexpectedBehaviorRecovered(x)
 	case TickCycleBegun:
 		l.tickCycleBegun(x)
 	case TickCompleted:
@@ -53,6 +57,8 @@ func (l *Logger) Notify(f Fact) {
 		l.eventRecordedByAggregate(x)
 	case MessageLoggedByAggregate:
 		l.messageLoggedByAggregate(x)
+	case AggregateHistorySeeded:
+		l.aggregateHistorySeeded(x)
 	case ProcessInstanceLoaded:
 		l.processInstanceLoaded(x)
 	case ProcessEventIgnored:
@@ -73,6 +79,8 @@ func (l *Logger) Notify(f Fact) {
 		l.timeoutScheduledByProcess(x)
 	case MessageLoggedByProcess:
 		l.messageLoggedByProcess(x)
+	case ProcessRootSeeded:
+		l.processRootSeeded(x)
 	case EventRecordedByIntegration:
 		l.eventRecordedByIntegration(x)
 	case MessageLoggedByIntegration:
@@ -115,6 +123,20 @@ func (l *Logger) dispatchBegun(f DispatchBegun) {
 	)
 }
 
+// dispatchLimitExceeded returns the log message for f.
+func (l *Logger) dispatchLimitExceeded(f DispatchLimitExceeded) {
+	l.log(
+		f.Envelope,
+		[]logging.Icon{
+			logging.InboundErrorIcon,
+			logging.SystemIcon,
+			logging.ErrorIcon,
+		},
+		"dispatch aborted",
+		f.Error.Error(),
+	)
+}
+
 // handlingCompleted returns the log message for f.
 func (l *Logger) handlingCompleted(f HandlingCompleted) {
 	if f.Error != nil {
@@ -156,6 +178,26 @@ func (l *Logger) handlingSkipped(f HandlingSkipped) {
 	)
 }
 
+// unexpectedBehaviorRecovered returns the log message for f.
+func (l *Logger) unexpectedBehaviorRecovered(f UnexpectedBehaviorRecovered) {
+	l.log(
+		f.Envelope,
+		[]logging.Icon{
+			logging.InboundErrorIcon,
+			logging.HandlerTypeIcon(f.Handler.HandlerType()),
+			logging.ErrorIcon,
+		},
+		f.Handler.Identity().Name,
+		fmt.Sprintf(
+			"recovered from unexpected behavior in %T.%s(): %s",
+			f.Implementation,
+			f.Method,
+			f.Description,
+		),
+		f.Location.String(),
+	)
+}
+
 // tickCycleBegun returns the log message for f.
 func (l *Logger) tickCycleBegun(f TickCycleBegun) {
 	l.log(
@@ -289,6 +331,26 @@ func (l *Logger) messageLoggedByAggregate(f MessageLoggedByAggregate) {
 	)
 }
 
+// aggregateHistorySeeded returns the log message for f.
+func (l *Logger) aggregateHistorySeeded(f AggregateHistorySeeded) {
+	for _, env := range f.EventEnvelopes {
+		mt := message.TypeOf(env.Message)
+
+		l.log(
+			env,
+			[]logging.Icon{
+				logging.OutboundIcon,
+				logging.AggregateIcon,
+				"",
+			},
+			f.Handler.Identity().Name+" "+f.InstanceID,
+			"seeded an event",
+			mt.String()+mt.Kind().Symbol(),
+			env.Message.MessageDescription(),
+		)
+	}
+}
+
 // processInstanceLoaded returns the log message for f.
 func (l *Logger) processInstanceLoaded(f ProcessInstanceLoaded) {
 	l.log(
@@ -440,6 +502,20 @@ func (l *Logger) messageLoggedByProcess(f MessageLoggedByProcess) {
 	)
 }
 
+// processRootSeeded returns the log message for f.
+func (l *Logger) processRootSeeded(f ProcessRootSeeded) {
+	l.log(
+		nil,
+		[]logging.Icon{
+			"",
+			logging.ProcessIcon,
+			"",
+		},
+		f.Handler.Identity().Name+" "+f.InstanceID,
+		"instance seeded",
+	)
+}
+
 // eventRecordedByIntegration returns the log message for f.
 func (l *Logger) eventRecordedByIntegration(f EventRecordedByIntegration) {
 	mt := message.TypeOf(f.EventEnvelope.Message)
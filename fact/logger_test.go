@@ -9,6 +9,7 @@ import (
 	. "github.com/dogmatiq/enginekit/enginetest/stubs"
 	"github.com/dogmatiq/testkit/envelope"
 	. "github.com/dogmatiq/testkit/fact"
+	"github.com/dogmatiq/testkit/location"
 	g "github.com/onsi/ginkgo/v2"
 	gm "github.com/onsi/gomega"
 )
@@ -154,6 +155,15 @@ var _ = g.Describe("type Logger", func() {
 				},
 			),
 
+			g.Entry(
+				"DispatchLimitExceeded",
+				"= 10  ∵ 10  ⋲ 10  ▽ ⚙ ✖  dispatch aborted ● <error>",
+				DispatchLimitExceeded{
+					Envelope: command,
+					Error:    errors.New("<error>"),
+				},
+			),
+
 			g.Entry(
 				"HandlingBegun",
 				"",
@@ -173,6 +183,22 @@ var _ = g.Describe("type Logger", func() {
 					Error:    errors.New("<error>"),
 				},
 			),
+			g.Entry(
+				"UnexpectedBehaviorRecovered",
+				"= 10  ∵ 10  ⋲ 10  ▽ ∴ ✖  <aggregate> ● recovered from unexpected behavior in *stubs.AggregateMessageHandlerStub.HandleCommand(): <description> ● <file>:123",
+				UnexpectedBehaviorRecovered{
+					Handler:        aggregate,
+					Envelope:       command,
+					Interface:      "AggregateMessageHandler",
+					Method:         "HandleCommand",
+					Implementation: aggregate.Handler(),
+					Description:    "<description>",
+					Location: location.Location{
+						File: "<file>",
+						Line: 123,
+					},
+				},
+			),
 			g.Entry(
 				"HandlingSkipped (handler type)",
 				"= 10  ∵ 10  ⋲ 10  ▼ ∴    <aggregate> ● handler skipped because aggregate handlers are disabled",
@@ -323,6 +349,17 @@ var _ = g.Describe("type Logger", func() {
 					LogArguments: []any{"message"},
 				},
 			),
+			g.Entry(
+				"AggregateHistorySeeded",
+				"= 10  ∵ 10  ⋲ 10  ▲ ∴    <aggregate> <instance> ● seeded an event ● stubs.EventStub[TypeA]! ● event(stubs.TypeA:A1, valid)",
+				AggregateHistorySeeded{
+					Handler:    aggregate,
+					InstanceID: "<instance>",
+					EventEnvelopes: []*envelope.Envelope{
+						event,
+					},
+				},
+			),
 
 			// processes ...
 
@@ -433,6 +470,14 @@ var _ = g.Describe("type Logger", func() {
 					LogArguments: []any{"message"},
 				},
 			),
+			g.Entry(
+				"ProcessRootSeeded",
+				"= --  ∵ --  ⋲ --    ≡    <process> <instance> ● instance seeded",
+				ProcessRootSeeded{
+					Handler:    process,
+					InstanceID: "<instance>",
+				},
+			),
 
 			// integrations ...
 
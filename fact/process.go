@@ -96,3 +96,11 @@ type MessageLoggedByProcess struct {
 	LogFormat    string
 	LogArguments []any
 }
+
+// ProcessRootSeeded indicates that the root of a process instance was set
+// directly, without the process message handler handling any message.
+type ProcessRootSeeded struct {
+	Handler    configkit.RichProcess
+	InstanceID string
+	Root       dogma.ProcessRoot
+}
@@ -0,0 +1,192 @@
+package clone
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Value returns a deep copy of v.
+//
+// Pointers, interfaces, slices, maps, arrays and structs (including their
+// unexported fields) are copied recursively. Pointer cycles are preserved in
+// the copy. Protocol Buffers messages are copied using [proto.Clone].
+//
+// Channels, functions and unsafe pointers can not be meaningfully copied, and
+// so are shared between v and the copy.
+func Value[T any](v T) T {
+	c := &cloner{
+		seen: map[pointer]reflect.Value{},
+	}
+
+	var r T
+
+	c.copy(
+		reflect.ValueOf(&r).Elem(),
+		reflect.ValueOf(&v).Elem(),
+	)
+
+	return r
+}
+
+var (
+	protoMessageType = reflect.TypeFor[proto.Message]()
+	timeType         = reflect.TypeFor[time.Time]()
+)
+
+// pointer uniquely identifies a pointer value that has already been copied.
+type pointer struct {
+	Type    reflect.Type
+	Address uintptr
+}
+
+// cloner performs a deep copy of a single value.
+type cloner struct {
+	seen map[pointer]reflect.Value
+}
+
+// copy sets dst to a deep copy of src. dst must be settable.
+func (c *cloner) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		c.copyPointer(dst, src)
+	case reflect.Interface:
+		c.copyInterface(dst, src)
+	case reflect.Struct:
+		c.copyStruct(dst, src)
+	case reflect.Slice:
+		c.copySlice(dst, src)
+	case reflect.Map:
+		c.copyMap(dst, src)
+	case reflect.Array:
+		c.copyArray(dst, src)
+	default:
+		dst.Set(src)
+	}
+}
+
+func (c *cloner) copyPointer(dst, src reflect.Value) {
+	if src.IsNil() {
+		return
+	}
+
+	p := pointer{src.Type(), src.Pointer()}
+
+	if v, ok := c.seen[p]; ok {
+		dst.Set(v)
+		return
+	}
+
+	if src.Type().Implements(protoMessageType) {
+		v := reflect.ValueOf(
+			proto.Clone(src.Interface().(proto.Message)),
+		)
+		c.seen[p] = v
+		dst.Set(v)
+		return
+	}
+
+	v := reflect.New(src.Type().Elem())
+	c.seen[p] = v
+	c.copy(v.Elem(), src.Elem())
+	dst.Set(v)
+}
+
+func (c *cloner) copyInterface(dst, src reflect.Value) {
+	if src.IsNil() {
+		return
+	}
+
+	elem := src.Elem()
+	v := reflect.New(elem.Type()).Elem()
+	c.copy(v, elem)
+	dst.Set(v)
+}
+
+func (c *cloner) copyStruct(dst, src reflect.Value) {
+	// Copy the entire struct first so that fields of kinds that are not
+	// copied recursively are retained.
+	dst.Set(src)
+
+	// Time values are immutable, and their location pointer is compared by
+	// identity, so they must not be copied recursively.
+	if src.Type() == timeType {
+		return
+	}
+
+	// Unexported fields can only be accessed via their address, so a struct
+	// obtained from a map or an interface must be copied to addressable
+	// memory first.
+	if !src.CanAddr() {
+		v := reflect.New(src.Type()).Elem()
+		v.Set(src)
+		src = v
+	}
+
+	for i := 0; i < src.NumField(); i++ {
+		c.copy(
+			accessible(dst.Field(i)),
+			accessible(src.Field(i)),
+		)
+	}
+}
+
+func (c *cloner) copySlice(dst, src reflect.Value) {
+	if src.IsNil() {
+		return
+	}
+
+	v := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+
+	for i := 0; i < src.Len(); i++ {
+		c.copy(v.Index(i), src.Index(i))
+	}
+
+	dst.Set(v)
+}
+
+func (c *cloner) copyMap(dst, src reflect.Value) {
+	if src.IsNil() {
+		return
+	}
+
+	t := src.Type()
+	v := reflect.MakeMapWithSize(t, src.Len())
+
+	iter := src.MapRange()
+	for iter.Next() {
+		k := reflect.New(t.Key()).Elem()
+		c.copy(k, iter.Key())
+
+		e := reflect.New(t.Elem()).Elem()
+		c.copy(e, iter.Value())
+
+		v.SetMapIndex(k, e)
+	}
+
+	dst.Set(v)
+}
+
+func (c *cloner) copyArray(dst, src reflect.Value) {
+	for i := 0; i < src.Len(); i++ {
+		c.copy(dst.Index(i), src.Index(i))
+	}
+}
+
+// accessible returns a value that refers to the same memory as v, but which
+// can be read and written even if v was obtained via an unexported struct
+// field.
+//
+// v must be addressable.
+func accessible(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+
+	return reflect.NewAt(
+		v.Type(),
+		unsafe.Pointer(v.UnsafeAddr()),
+	).Elem()
+}
@@ -0,0 +1,100 @@
+package clone_test
+
+import (
+	"time"
+
+	. "github.com/dogmatiq/testkit/internal/clone"
+	"github.com/dogmatiq/testkit/internal/fixtures"
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+)
+
+type node struct {
+	Value    string
+	children []*node
+	parent   *node
+	attrs    map[string][]int
+	any      any
+	array    [2]*int
+	time     time.Time
+	fn       func()
+}
+
+var _ = g.Describe("func Value()", func() {
+	g.It("returns a deep copy of the value", func() {
+		n := 1
+		now := time.Now()
+
+		root := &node{
+			Value: "<root>",
+			attrs: map[string][]int{
+				"<key>": {1, 2, 3},
+			},
+			any:   &node{Value: "<any>"},
+			array: [2]*int{&n, nil},
+			time:  now,
+		}
+		root.children = []*node{
+			{Value: "<child>", parent: root},
+		}
+
+		c := Value(root)
+
+		gm.Expect(c).To(gm.Equal(root))
+		gm.Expect(c).NotTo(gm.BeIdenticalTo(root))
+		gm.Expect(c.children[0]).NotTo(gm.BeIdenticalTo(root.children[0]))
+		gm.Expect(c.children[0].parent).To(gm.BeIdenticalTo(c))
+		gm.Expect(c.any).NotTo(gm.BeIdenticalTo(root.any))
+		gm.Expect(c.array[0]).NotTo(gm.BeIdenticalTo(root.array[0]))
+		gm.Expect(c.time).To(gm.Equal(now))
+
+		c.attrs["<key>"][0] = 100
+		c.any.(*node).Value = "<changed>"
+		*c.array[0] = 100
+
+		gm.Expect(root.attrs["<key>"]).To(gm.Equal([]int{1, 2, 3}))
+		gm.Expect(root.any.(*node).Value).To(gm.Equal("<any>"))
+		gm.Expect(n).To(gm.Equal(1))
+	})
+
+	g.It("shares functions with the original value", func() {
+		called := false
+		v := node{
+			fn: func() { called = true },
+		}
+
+		Value(v).fn()
+
+		gm.Expect(called).To(gm.BeTrue())
+	})
+
+	g.It("copies protocol buffers messages", func() {
+		m := &fixtures.ProtoMessage{Value: "<value>"}
+
+		c := Value(m)
+
+		gm.Expect(c).NotTo(gm.BeIdenticalTo(m))
+		gm.Expect(proto.Equal(c, m)).To(gm.BeTrue())
+	})
+
+	g.It("copies values within interfaces", func() {
+		var v any = map[string]node{
+			"<key>": {Value: "<value>", attrs: map[string][]int{"<attr>": {1}}},
+		}
+
+		c := Value(v)
+
+		gm.Expect(c).To(gm.Equal(v))
+
+		c.(map[string]node)["<key>"].attrs["<attr>"][0] = 100
+		gm.Expect(v.(map[string]node)["<key>"].attrs["<attr>"]).To(gm.Equal([]int{1}))
+	})
+
+	g.It("returns nil values unchanged", func() {
+		var v any
+
+		gm.Expect(Value(v)).To(gm.BeNil())
+		gm.Expect(Value[*node](nil)).To(gm.BeNil())
+	})
+})
@@ -0,0 +1,2 @@
+// Package clone produces deep copies of arbitrary Go values.
+package clone
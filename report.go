@@ -50,6 +50,10 @@ func (c ReportGenerationContext) renderMessage(m dogma.Message) string {
 	return c.printer.Format(m)
 }
 
+func (c ReportGenerationContext) renderValue(v any) string {
+	return c.printer.Format(v)
+}
+
 // Report is a report on the outcome of an expectation.
 type Report struct {
 	// TreeOk is true if the "tree" that the expectation belongs to passed.
@@ -2,6 +2,7 @@ package testkit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -14,6 +15,7 @@ import (
 	"github.com/dogmatiq/iago/must"
 	"github.com/dogmatiq/testkit/engine"
 	"github.com/dogmatiq/testkit/fact"
+	"go.uber.org/multierr"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -28,9 +30,18 @@ type Test struct {
 	executor         CommandExecutor
 	predicateOptions PredicateOptions
 	operationOptions []engine.OperationOption
+	engineOptions    []engine.Option
 	annotations      []Annotation
 }
 
+// defaultCausationDepthLimit is the maximum length of a chain of messages that
+// may be caused by a single action before the test fails.
+//
+// It prevents handlers that produce messages in a loop from causing the test
+// to run forever. It can be changed, or removed entirely by setting it to zero,
+// using WithUnsafeEngineOptions().
+const defaultCausationDepthLimit = 1000
+
 // Begin starts a new test.
 func Begin(
 	t TestingT,
@@ -59,10 +70,10 @@ func BeginContext(
 		testingT:     t,
 		app:          cfg,
 		virtualClock: time.Now(),
-		engine: engine.MustNew(
-			cfg,
+		engineOptions: []engine.Option{
 			engine.EnableProjectionCompactionDuringHandling(true),
-		),
+			engine.WithCausationDepthLimit(defaultCausationDepthLimit),
+		},
 		operationOptions: []engine.OperationOption{
 			engine.EnableProjections(false),
 			engine.EnableIntegrations(false),
@@ -78,6 +89,8 @@ func BeginContext(
 		opt.applyTestOption(test)
 	}
 
+	test.engine = engine.MustNew(cfg, test.engineOptions...)
+
 	return test
 }
 
@@ -101,8 +114,11 @@ func (t *Test) Expect(act Action, e Expectation) *Test {
 	t.testingT.Helper()
 
 	s := PredicateScope{
-		App:     t.app,
-		Options: t.predicateOptions,
+		App:          t.app,
+		Options:      t.predicateOptions,
+		requirements: &predicateRequirements{},
+		ctx:          t.ctx,
+		engine:       t.engine,
 	}
 
 	act.ConfigurePredicate(&s.Options)
@@ -115,16 +131,61 @@ func (t *Test) Expect(act Action, e Expectation) *Test {
 		return t // required when using a mock testingT that does not panic
 	}
 
+	// handlerErrors contains the errors returned by message handlers while
+	// performing the action, and recovered contains those that were caused by
+	// unexpected behavior.
+	var (
+		handlerErrors, recovered []error
+		recovering               = map[handlerInvocation]struct{}{}
+	)
+
+	opts := []engine.OperationOption{
+		engine.WithObserver(p),
+		engine.WithObserver(
+			fact.ObserverFunc(func(f fact.Fact) {
+				switch x := f.(type) {
+				case fact.UnexpectedBehaviorRecovered:
+					recovering[handlerInvocation{x.Handler.Identity().Name, x.Envelope.MessageID}] = struct{}{}
+				case fact.HandlingCompleted:
+					if x.Error == nil {
+						return
+					}
+
+					handlerErrors = append(handlerErrors, x.Error)
+
+					if _, ok := recovering[handlerInvocation{x.Handler.Identity().Name, x.Envelope.MessageID}]; ok {
+						recovered = append(recovered, x.Error)
+					}
+				}
+			}),
+		),
+	}
+
+	if s.requirements.ExpectUnexpectedBehavior {
+		opts = append(opts, engine.RecoverUnexpectedBehavior(true))
+	}
+
 	// Using a defer inside a closure satisfies the requirements of the
 	// Expectation and Predicate interfaces which state that p.Done() must
 	// be called exactly once, and that it must be called before calling
 	// p.Report().
 	if err := func() error {
 		defer p.Done()
-		return t.doAction(act, engine.WithObserver(p))
+		return t.doAction(act, opts...)
 	}(); err != nil {
-		t.testingT.Fatal(err)
-		return t // required when using a mock testingT that does not panic
+		// Errors returned by handlers are reported on by the predicates that
+		// expect them, any other error fails the test outright.
+		var expected []error
+		if s.requirements.ExpectHandlerErrors {
+			expected = handlerErrors
+		} else if s.requirements.ExpectUnexpectedBehavior {
+			expected = recovered
+		}
+
+		if err := unexpectedErrors(err, expected); err != nil {
+			t.testingT.Fatal(err)
+			return t // required when using a mock testingT that does not panic
+		}
 	}
 
 	options := []dapper.Option{
@@ -176,6 +237,46 @@ func (t *Test) Expect(act Action, e Expectation) *Test {
 	return t
 }
 
+// handlerInvocation identifies a single invocation of a message handler.
+type handlerInvocation struct {
+	Handler   string
+	MessageID string
+}
+
+// unexpectedErrors returns the errors within err, which is the error returned
+// by an action, that are not among the expected handler errors.
+func unexpectedErrors(err error, expected []error) error {
+	var unexpected error
+
+	for _, e := range multierr.Errors(err) {
+		if !isHandlerError(e, expected) {
+			unexpected = multierr.Append(unexpected, e)
+		}
+	}
+
+	return unexpected
+}
+
+// isHandlerError returns true if err is the engine's description of one of the
+// given errors returned by a message handler.
+func isHandlerError(err error, handlerErrors []error) bool {
+	// The engine wraps each handler error with the handler's identity, so only
+	// the unwrapped error is compared. Otherwise, an error that happens to
+	// match a handler error, such as a context error, would be hidden.
+	cause := errors.Unwrap(err)
+	if cause == nil {
+		return false
+	}
+
+	for _, h := range handlerErrors {
+		if errors.Is(cause, h) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // CommandExecutor returns a dogma.CommandExecutor which can be used to execute
 // commands within the context of this test.
 //
@@ -188,6 +289,108 @@ func (t *Test) CommandExecutor() dogma.CommandExecutor {
 	return &t.executor
 }
 
+// AggregateRoot returns the root of the aggregate instance with the given ID.
+//
+// The root is rebuilt from the instance's history, and hence modifying it has
+// no effect on the test's state. ok is false if the instance does not exist.
+//
+// It panics if the application does not have an aggregate message handler
+// named handler.
+func (t *Test) AggregateRoot(handler, id string) (r dogma.AggregateRoot, ok bool) {
+	t.testingT.Helper()
+
+	r, ok, err := t.engine.AggregateRoot(t.ctx, handler, id)
+	if err != nil {
+		t.testingT.Fatal(err)
+	}
+
+	return r, ok
+}
+
+// AggregateHistory returns the events recorded by the aggregate instance with
+// the given ID, in the order they were recorded.
+//
+// The events are copies, and hence modifying them has no effect on the test's
+// state.
+//
+// It panics if the application does not have an aggregate message handler
+// named handler.
+func (t *Test) AggregateHistory(handler, id string) []dogma.Event {
+	t.testingT.Helper()
+
+	envs, err := t.engine.AggregateHistory(t.ctx, handler, id)
+	if err != nil {
+		t.testingT.Fatal(err)
+	}
+
+	var events []dogma.Event
+	for _, env := range envs {
+		events = append(events, env.Message.(dogma.Event))
+	}
+
+	return events
+}
+
+// ProcessRoot returns a copy of the root of the process instance with the
+// given ID.
+//
+// Modifying the root has no effect on the test's state. ok is false if the
+// instance does not exist.
+//
+// It panics if the application does not have a process message handler named
+// handler.
+func (t *Test) ProcessRoot(handler, id string) (r dogma.ProcessRoot, ok bool) {
+	t.testingT.Helper()
+
+	r, ok, err := t.engine.ProcessRoot(t.ctx, handler, id)
+	if err != nil {
+		t.testingT.Fatal(err)
+	}
+
+	return r, ok
+}
+
+// ListInstances returns the IDs of the aggregate or process instances managed
+// by the handler named handler that currently exist, in lexicographical order.
+//
+// It panics if the application does not have an aggregate or process message
+// handler named handler.
+func (t *Test) ListInstances(handler string) []string {
+	t.testingT.Helper()
+
+	ids, err := t.engine.ListInstances(t.ctx, handler)
+	if err != nil {
+		t.testingT.Fatal(err)
+	}
+
+	return ids
+}
+
+// PendingTimeouts returns the timeouts that have been scheduled by the process
+// message handler named handler but not yet handled, in the order they are
+// scheduled to occur.
+//
+// The timeouts are copies, and hence modifying them has no effect on the
+// test's state.
+//
+// It panics if the application does not have a process message handler named
+// handler.
+func (t *Test) PendingTimeouts(handler string) []dogma.Timeout {
+	t.testingT.Helper()
+
+	envs, err := t.engine.PendingTimeouts(t.ctx, handler)
+	if err != nil {
+		t.testingT.Fatal(err)
+	}
+
+	var timeouts []dogma.Timeout
+	for _, env := range envs {
+		timeouts = append(timeouts, env.Message.(dogma.Timeout))
+	}
+
+	return timeouts
+}
+
 // Annotate adds an annotation to v.
 //
 // The annotation text is displayed whenever v is rendered in a test report.
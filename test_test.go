@@ -3,6 +3,7 @@ package testkit_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/dogmatiq/dogma"
 	. "github.com/dogmatiq/enginekit/enginetest/stubs"
@@ -337,4 +338,202 @@ var _ = g.Describe("type Test", func() {
 			)(t)
 		})
 	})
+
+	g.Describe("state inspection", func() {
+		var (
+			app  *ApplicationStub
+			t    *testingmock.T
+			test *Test
+		)
+
+		g.BeforeEach(func() {
+			app = &ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", "0b8d6c8e-5f2a-4f4e-9c53-1e7f3b9a2d61")
+
+					c.RegisterAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "5d1f2a3b-4c5d-4e6f-8a7b-9c0d1e2f3a4b")
+							c.Routes(
+								dogma.HandlesCommand[CommandStub[TypeA]](),
+								dogma.RecordsEvent[EventStub[TypeA]](),
+							)
+						},
+						RouteCommandToInstanceFunc: func(m dogma.Command) string {
+							return "<instance-" + m.MessageDescription() + ">"
+						},
+						HandleCommandFunc: func(
+							_ dogma.AggregateRoot,
+							s dogma.AggregateCommandScope,
+							_ dogma.Command,
+						) {
+							s.RecordEvent(EventA1)
+						},
+					})
+
+					c.RegisterProcess(&ProcessMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProcessConfigurer) {
+							c.Identity("<process>", "6e2a3b4c-5d6e-4f70-9b8c-0d1e2f3a4b5c")
+							c.Routes(
+								dogma.HandlesEvent[EventStub[TypeA]](),
+								dogma.ExecutesCommand[CommandStub[TypeB]](),
+								dogma.SchedulesTimeout[TimeoutStub[TypeA]](),
+							)
+						},
+						RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+							return "<instance>", true, nil
+						},
+						HandleEventFunc: func(
+							_ context.Context,
+							r dogma.ProcessRoot,
+							s dogma.ProcessEventScope,
+							_ dogma.Event,
+						) error {
+							r.(*ProcessRootStub).Value = "<value>"
+							s.ScheduleTimeout(TimeoutA1, s.RecordedAt().Add(1*time.Hour))
+							return nil
+						},
+					})
+				},
+			}
+
+			t = &testingmock.T{}
+			test = Begin(t, app)
+		})
+
+		g.Describe("func AggregateRoot()", func() {
+			g.It("returns the root with the instance's history applied", func() {
+				test.Prepare(
+					ExecuteCommand(CommandA1),
+					ExecuteCommand(CommandA1),
+				)
+
+				r, ok := test.AggregateRoot("<aggregate>", "<instance-command(stubs.TypeA:A1, valid)>")
+				gm.Expect(ok).To(gm.BeTrue())
+				gm.Expect(r).To(gm.Equal(
+					&AggregateRootStub{
+						AppliedEvents: []dogma.Event{EventA1, EventA1},
+					},
+				))
+			})
+
+			g.It("returns false if the instance does not exist", func() {
+				_, ok := test.AggregateRoot("<aggregate>", "<unknown>")
+				gm.Expect(ok).To(gm.BeFalse())
+			})
+
+			g.It("panics if the handler is not an aggregate", func() {
+				gm.Expect(func() {
+					test.AggregateRoot("<process>", "<instance>")
+				}).To(gm.PanicWith(`the application does not have an aggregate named "<process>"`))
+			})
+		})
+
+		g.Describe("func AggregateHistory()", func() {
+			g.It("returns the events recorded by the instance", func() {
+				test.Prepare(
+					ExecuteCommand(CommandA1),
+				)
+
+				gm.Expect(
+					test.AggregateHistory("<aggregate>", "<instance-command(stubs.TypeA:A1, valid)>"),
+				).To(gm.Equal(
+					[]dogma.Event{EventA1},
+				))
+			})
+
+			g.It("returns an empty slice if the instance does not exist", func() {
+				gm.Expect(test.AggregateHistory("<aggregate>", "<unknown>")).To(gm.BeEmpty())
+			})
+		})
+
+		g.Describe("func ProcessRoot()", func() {
+			g.It("returns a copy of the root", func() {
+				test.Prepare(
+					RecordEvent(EventA1),
+				)
+
+				r, ok := test.ProcessRoot("<process>", "<instance>")
+				gm.Expect(ok).To(gm.BeTrue())
+				gm.Expect(r).To(gm.Equal(
+					&ProcessRootStub{Value: "<value>"},
+				))
+
+				r.(*ProcessRootStub).Value = "<modified>"
+
+				r, _ = test.ProcessRoot("<process>", "<instance>")
+				gm.Expect(r).To(gm.Equal(
+					&ProcessRootStub{Value: "<value>"},
+				))
+			})
+
+			g.It("returns false if the instance does not exist", func() {
+				_, ok := test.ProcessRoot("<process>", "<unknown>")
+				gm.Expect(ok).To(gm.BeFalse())
+			})
+
+			g.It("panics if the handler is not a process", func() {
+				gm.Expect(func() {
+					test.ProcessRoot("<aggregate>", "<instance>")
+				}).To(gm.PanicWith(`the application does not have a process named "<aggregate>"`))
+			})
+		})
+
+		g.Describe("func ListInstances()", func() {
+			g.It("returns the IDs of the aggregate instances in order", func() {
+				test.Prepare(
+					ExecuteCommand(CommandA2),
+					ExecuteCommand(CommandA1),
+				)
+
+				gm.Expect(test.ListInstances("<aggregate>")).To(gm.Equal(
+					[]string{
+						"<instance-command(stubs.TypeA:A1, valid)>",
+						"<instance-command(stubs.TypeA:A2, valid)>",
+					},
+				))
+			})
+
+			g.It("returns the IDs of the process instances", func() {
+				test.Prepare(
+					RecordEvent(EventA1),
+				)
+
+				gm.Expect(test.ListInstances("<process>")).To(gm.Equal(
+					[]string{"<instance>"},
+				))
+			})
+
+			g.It("returns an empty slice if there are no instances", func() {
+				gm.Expect(test.ListInstances("<process>")).To(gm.BeEmpty())
+			})
+
+			g.It("panics if the handler is neither an aggregate nor a process", func() {
+				gm.Expect(func() {
+					test.ListInstances("<unknown>")
+				}).To(gm.PanicWith(`the application does not have an aggregate or process named "<unknown>"`))
+			})
+		})
+
+		g.Describe("func PendingTimeouts()", func() {
+			g.It("returns the timeouts that have not yet been handled", func() {
+				test.Prepare(
+					RecordEvent(EventA1),
+				)
+
+				gm.Expect(test.PendingTimeouts("<process>")).To(gm.Equal(
+					[]dogma.Timeout{TimeoutA1},
+				))
+			})
+
+			g.It("does not return timeouts that have been handled", func() {
+				test.Prepare(
+					RecordEvent(EventA1),
+					AdvanceTime(ByDuration(1*time.Hour)),
+				)
+
+				gm.Expect(test.PendingTimeouts("<process>")).To(gm.BeEmpty())
+			})
+		})
+	})
 })
@@ -1,6 +1,7 @@
 package testkit
 
 import (
+	"math/rand/v2"
 	"time"
 
 	"github.com/dogmatiq/testkit/engine"
@@ -40,3 +41,51 @@ func WithUnsafeOperationOptions(options ...engine.OperationOption) TestOption {
 		t.operationOptions = append(t.operationOptions, options...)
 	})
 }
+
+// WithUnsafeEngineOptions returns a TestOption that applies a set of engine
+// options when the test's engine is created.
+//
+// This function is provided for forward-compatibility with engine options and
+// for low level control of the engine's behavior, such as raising the limits
+// placed on the number of messages that may be dispatched by a single action.
+//
+// The provided options may override options that the Test sets during its
+// normal operation and should be used with caution.
+func WithUnsafeEngineOptions(options ...engine.Option) TestOption {
+	return testOptionFunc(func(t *Test) {
+		t.engineOptions = append(t.engineOptions, options...)
+	})
+}
+
+// RandomizeHandlerOrder returns a TestOption that causes the test's engine to
+// invoke handlers in a random order.
+//
+// This exposes application code that inadvertently depends on the order in
+// which handlers are invoked. The seed used to randomize the order is written
+// to the test log, so that a failing test can be reproduced using
+// RandomizeHandlerOrderWithSeed().
+//
+// See engine.RandomizeHandlerOrder() for more information.
+func RandomizeHandlerOrder() TestOption {
+	return RandomizeHandlerOrderWithSeed(rand.Uint64())
+}
+
+// RandomizeHandlerOrderWithSeed returns a TestOption that causes the test's
+// engine to invoke handlers in a random order, as determined by seed.
+//
+// It is typically used to reproduce a test failure that occurred when using
+// RandomizeHandlerOrder().
+func RandomizeHandlerOrderWithSeed(seed uint64) TestOption {
+	return testOptionFunc(func(t *Test) {
+		logf(
+			t.testingT,
+			"--- randomizing handler order, reproduce using RandomizeHandlerOrderWithSeed(%d) ---",
+			seed,
+		)
+
+		t.engineOptions = append(
+			t.engineOptions,
+			engine.RandomizeHandlerOrder(seed),
+		)
+	})
+}
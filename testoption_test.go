@@ -7,6 +7,7 @@ import (
 	"github.com/dogmatiq/dogma"
 	. "github.com/dogmatiq/enginekit/enginetest/stubs"
 	. "github.com/dogmatiq/testkit"
+	"github.com/dogmatiq/testkit/engine"
 	"github.com/dogmatiq/testkit/internal/testingmock"
 	g "github.com/onsi/ginkgo/v2"
 	gm "github.com/onsi/gomega"
@@ -98,3 +99,123 @@ var _ = g.Describe("func WithMessageComparator()", func() {
 			)
 	})
 })
+
+var _ = g.Describe("func WithUnsafeEngineOptions()", func() {
+	var (
+		app dogma.Application
+		t   *testingmock.T
+	)
+
+	g.BeforeEach(func() {
+		aggregate := &AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "1b0a2b1e-dcd6-4e3f-9a4b-2c0a6f7e3a11")
+				c.Routes(
+					dogma.HandlesCommand[CommandStub[TypeA]](),
+					dogma.RecordsEvent[EventStub[TypeA]](),
+				)
+			},
+			RouteCommandToInstanceFunc: func(dogma.Command) string {
+				return "<instance>"
+			},
+			HandleCommandFunc: func(
+				_ dogma.AggregateRoot,
+				s dogma.AggregateCommandScope,
+				_ dogma.Command,
+			) {
+				s.RecordEvent(EventA1)
+			},
+		}
+
+		process := &ProcessMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProcessConfigurer) {
+				c.Identity("<process>", "6d9a7c1f-2b4e-4f0a-8c3d-5e7b9a1c2d4f")
+				c.Routes(
+					dogma.HandlesEvent[EventStub[TypeA]](),
+					dogma.ExecutesCommand[CommandStub[TypeA]](),
+				)
+			},
+			RouteEventToInstanceFunc: func(context.Context, dogma.Event) (string, bool, error) {
+				return "<instance>", true, nil
+			},
+			HandleEventFunc: func(
+				_ context.Context,
+				_ dogma.ProcessRoot,
+				s dogma.ProcessEventScope,
+				_ dogma.Event,
+			) error {
+				s.ExecuteCommand(CommandA1)
+				return nil
+			},
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "0f8e4b2a-7c1d-4e6f-9a3b-8d2c5e1f4a7b")
+				c.RegisterAggregate(aggregate)
+				c.RegisterProcess(process)
+			},
+		}
+
+		t = &testingmock.T{
+			FailSilently: true,
+		}
+	})
+
+	g.It("applies the options to the test's engine", func() {
+		Begin(
+			t,
+			app,
+			WithUnsafeEngineOptions(
+				engine.WithCausationDepthLimit(3),
+			),
+		).Prepare(
+			ExecuteCommand(CommandA1),
+		)
+
+		gm.Expect(t.Failed()).To(gm.BeTrue())
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"dispatch aborted after 3 messages, the causation depth limit of 3 was exceeded, the messages appear to be caused by a loop: stubs.EventStub[TypeA] -> stubs.CommandStub[TypeA] -> stubs.EventStub[TypeA]",
+		))
+	})
+
+	g.It("allows the default causation depth limit to be removed", func() {
+		Begin(
+			t,
+			app,
+			WithUnsafeEngineOptions(
+				engine.WithCausationDepthLimit(0),
+				engine.WithDispatchLimit(1005),
+			),
+		).Prepare(
+			ExecuteCommand(CommandA1),
+		)
+
+		gm.Expect(t.Failed()).To(gm.BeTrue())
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"dispatch aborted after 1005 messages, the dispatch limit of 1005 messages was exceeded, the messages appear to be caused by a loop: stubs.EventStub[TypeA] -> stubs.CommandStub[TypeA] -> stubs.EventStub[TypeA]",
+		))
+	})
+})
+
+var _ = g.Describe("func RandomizeHandlerOrderWithSeed()", func() {
+	g.It("writes the seed to the test log", func() {
+		app := &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "3d6c1e0a-5b7f-4c2d-9e8a-1f4b6d2c7a90")
+			},
+		}
+
+		t := &testingmock.T{}
+
+		Begin(
+			t,
+			app,
+			RandomizeHandlerOrderWithSeed(123),
+		)
+
+		gm.Expect(t.Logs).To(gm.ContainElement(
+			"--- randomizing handler order, reproduce using RandomizeHandlerOrderWithSeed(123) ---",
+		))
+	})
+})